@@ -0,0 +1,61 @@
+package smartpagelist
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+)
+
+// Codec 定义单个列表元素与字节之间的编解码方式
+// List[T] 通过 Codec[T] 解耦元素类型与底层状态存储的字节表示，
+// 使得除字符串外的结构化数据（如链码资产）无需先手动序列化成字符串
+type Codec[T any] interface {
+	Encode(value T) ([]byte, error)
+	Decode(data []byte) (T, error)
+}
+
+// JSONCodec 是默认编解码器，使用 encoding/json 编解码元素，
+// 对 T=string 的场景复现了早期版本 List 的行为
+type JSONCodec[T any] struct{}
+
+func (JSONCodec[T]) Encode(value T) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (JSONCodec[T]) Decode(data []byte) (T, error) {
+	var value T
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+// GobCodec 使用 encoding/gob 编解码元素，每个元素独立编码，
+// 不依赖跨元素的流式上下文，因此可与 PushBack/Get 等按元素随机访问的场景配合使用
+// 注意：gob 编码结果不是合法 JSON 片段，需配合 LengthPrefixedFramer 使用
+type GobCodec[T any] struct{}
+
+func (GobCodec[T]) Encode(value T) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (GobCodec[T]) Decode(data []byte) (T, error) {
+	var value T
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&value)
+	return value, err
+}
+
+// RawBytesCodec 跳过编解码往返，直接将 []byte 元素原样存取，
+// 适用于已经完成序列化、不希望再承担一次编解码开销的场景
+// 注意：原始字节通常不是合法 JSON 片段，需配合 LengthPrefixedFramer 使用
+type RawBytesCodec struct{}
+
+func (RawBytesCodec) Encode(value []byte) ([]byte, error) {
+	return value, nil
+}
+
+func (RawBytesCodec) Decode(data []byte) ([]byte, error) {
+	return data, nil
+}
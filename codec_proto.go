@@ -0,0 +1,31 @@
+//go:build smartpagelist_proto
+
+package smartpagelist
+
+import (
+	"google.golang.org/protobuf/proto"
+)
+
+// ProtoCodec 使用 protobuf 编解码元素，要求 T 实现 proto.Message
+//
+// 该文件通过 smartpagelist_proto 构建标签隔离，避免未使用 protobuf 的
+// 调用方被迫引入 google.golang.org/protobuf 依赖；需要时在构建命令中加入
+// `-tags smartpagelist_proto` 并在 go.mod 中引入该依赖
+type ProtoCodec[T proto.Message] struct {
+	// New 用于构造一个零值 T，Decode 时作为 proto.Unmarshal 的目标
+	// T 通常是指针类型（如 *pb.Asset），因此需要显式提供构造函数
+	New func() T
+}
+
+func (c ProtoCodec[T]) Encode(value T) ([]byte, error) {
+	return proto.Marshal(value)
+}
+
+func (c ProtoCodec[T]) Decode(data []byte) (T, error) {
+	value := c.New()
+	if err := proto.Unmarshal(data, value); err != nil {
+		var zero T
+		return zero, err
+	}
+	return value, nil
+}
@@ -0,0 +1,238 @@
+package smartpagelist
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// cursorToken 是 Cursor.Token 序列化后在客户端与合约之间传递的分页游标
+// 由于链码调用是无状态的，游标的完整定位信息（逻辑索引、创建游标时的
+// 列表总量快照）都需要下沉进 token，才能在下一次调用时复原游标位置
+type cursorToken struct {
+	Index      int `json:"index"`
+	TotalCount int `json:"totalCountAtSnapshot"`
+}
+
+// Cursor 支持按顺序/逆序遍历列表，并缓存当前所在页以避免在同一页内的
+// 相邻移动（Next/Prev）重复调用 GetPage
+//
+// 游标内部只记录逻辑索引 idx，物理页码/页内偏移在每次 Value 时通过
+// list.locatePhysical 按需换算，因此能正确处理 PopFront 产生的头部跳过
+// 与 Sparse 模式产生的墓碑；cachedPageNum/cachedPage 仅用于同页内相邻
+// 移动时避免重复调用 GetPage，不参与定位本身
+type Cursor[T any] struct {
+	list *List[T]
+
+	// totalCount 是游标创建（或从 token 恢复）时读取到的列表总量快照，
+	// 决定了 First/Last/Next/Prev/Seek 的边界
+	totalCount int
+	// resumedFrom 记录 token 中携带的快照总量；为 0 表示该游标不是从
+	// token 恢复而来。配合 SnapshotGrew 可以告知调用方列表在游标创建
+	// 之后又发生了增长
+	resumedFrom int
+
+	idx   int
+	valid bool
+
+	cachedPageNum int
+	cachedPage    []T
+	hasCached     bool
+}
+
+// Cursor 创建一个指向列表起始位置的新游标
+func (l *List[T]) Cursor() (*Cursor[T], error) {
+	meta, err := l.getMeta()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cursor[T]{list: l, totalCount: meta.TotalCount}
+	c.First()
+	return c, nil
+}
+
+// CursorFrom 根据 Token 恢复一个游标，恢复后的游标以恢复时刻的列表总量
+// 作为新的边界快照；可通过 SnapshotGrew 判断列表是否在 token 生成之后
+// 又发生了增长
+func (l *List[T]) CursorFrom(token []byte) (*Cursor[T], error) {
+	var ct cursorToken
+	if err := json.Unmarshal(token, &ct); err != nil {
+		return nil, fmt.Errorf("unmarshal cursor token failed: %w", err)
+	}
+
+	meta, err := l.getMeta()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cursor[T]{
+		list:        l,
+		totalCount:  meta.TotalCount,
+		resumedFrom: ct.TotalCount,
+		idx:         ct.Index,
+	}
+	c.valid = c.idx >= 0 && c.idx < c.totalCount
+	return c, nil
+}
+
+// SnapshotGrew 报告列表是否在 token 生成之后又发生了增长
+// 只有通过 CursorFrom 恢复的游标才能回答该问题，直接创建的游标始终返回 false
+func (c *Cursor[T]) SnapshotGrew() bool {
+	return c.resumedFrom > 0 && c.totalCount > c.resumedFrom
+}
+
+// Valid 判断游标当前是否指向一个有效元素
+func (c *Cursor[T]) Valid() bool {
+	return c.valid
+}
+
+// First 将游标移动到第一个元素
+func (c *Cursor[T]) First() bool {
+	if c.totalCount == 0 {
+		c.valid = false
+		return false
+	}
+	c.idx = 0
+	c.valid = true
+	return true
+}
+
+// Last 将游标移动到最后一个元素
+func (c *Cursor[T]) Last() bool {
+	if c.totalCount == 0 {
+		c.valid = false
+		return false
+	}
+	c.idx = c.totalCount - 1
+	c.valid = true
+	return true
+}
+
+// Seek 将游标移动到指定索引；索引越界时游标失效并返回 false
+func (c *Cursor[T]) Seek(index int) bool {
+	if index < 0 || index >= c.totalCount {
+		c.valid = false
+		return false
+	}
+	c.idx = index
+	c.valid = true
+	return true
+}
+
+// Next 将游标移动到下一个元素；已到达末尾时游标失效并返回 false
+func (c *Cursor[T]) Next() bool {
+	if !c.valid {
+		return false
+	}
+	return c.Seek(c.idx + 1)
+}
+
+// Prev 将游标移动到上一个元素；已到达起始位置时游标失效并返回 false
+func (c *Cursor[T]) Prev() bool {
+	if !c.valid {
+		return false
+	}
+	return c.Seek(c.idx - 1)
+}
+
+// Value 返回游标当前指向的元素
+//
+// 定位通过 list.locatePhysical 按逻辑索引实时换算物理页码/页内偏移，与
+// Get 共用同一套规则，因此在列表发生过 PopFront（头部跳过）或 Sparse
+// RemoveAt（墓碑）之后仍能返回正确的元素，而不是像旧版本那样假设
+// FirstPageNumber/HeadOffset 始终为零
+func (c *Cursor[T]) Value() (T, error) {
+	var zero T
+	if !c.valid {
+		return zero, ErrIndexOutOfRange
+	}
+
+	meta, err := c.list.getMeta()
+	if err != nil {
+		return zero, err
+	}
+	pageNumber, offset, err := c.list.locatePhysical(meta, c.idx)
+	if err != nil {
+		return zero, err
+	}
+
+	page, err := c.loadPage(pageNumber)
+	if err != nil {
+		return zero, err
+	}
+	if offset < 0 || offset >= len(page) {
+		return zero, fmt.Errorf("data inconsistency detected [key:%s, index:%d, page:%d, offset:%d]: page has %d items", c.list.key, c.idx, pageNumber, offset, len(page))
+	}
+	return page[offset], nil
+}
+
+// loadPage 返回游标缓存的当前页，跨页移动时才重新调用 GetPage
+func (c *Cursor[T]) loadPage(pageNumber int) ([]T, error) {
+	if c.hasCached && c.cachedPageNum == pageNumber {
+		return c.cachedPage, nil
+	}
+
+	values, err := c.list.GetPage(pageNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	c.cachedPageNum = pageNumber
+	c.cachedPage = values
+	c.hasCached = true
+	return values, nil
+}
+
+// Token 将游标当前位置序列化为可在链码调用之间传递的分页令牌
+func (c *Cursor[T]) Token() ([]byte, error) {
+	if !c.valid {
+		return nil, ErrIndexOutOfRange
+	}
+
+	token := cursorToken{Index: c.idx, TotalCount: c.totalCount}
+	data, err := json.Marshal(token)
+	if err != nil {
+		return nil, fmt.Errorf("marshal cursor token failed: %w", err)
+	}
+	return data, nil
+}
+
+// GetRange 以 token 分页的方式读取一段元素，返回结果与指向下一页起始位置
+// 的 nextToken；pageToken 为空时从列表起始位置开始，nextToken 为空表示
+// 已经到达列表末尾，风格上类似 gRPC/ES scroll 分页
+func (l *List[T]) GetRange(pageToken []byte, limit int) ([]T, []byte, error) {
+	if limit <= 0 {
+		return nil, nil, fmt.Errorf("limit must be > 0")
+	}
+
+	var cursor *Cursor[T]
+	var err error
+	if len(pageToken) == 0 {
+		cursor, err = l.Cursor()
+	} else {
+		cursor, err = l.CursorFrom(pageToken)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items := make([]T, 0, limit)
+	for cursor.Valid() && len(items) < limit {
+		value, err := cursor.Value()
+		if err != nil {
+			return nil, nil, err
+		}
+		items = append(items, value)
+		cursor.Next()
+	}
+
+	if !cursor.Valid() {
+		return items, nil, nil
+	}
+
+	nextToken, err := cursor.Token()
+	if err != nil {
+		return nil, nil, err
+	}
+	return items, nextToken, nil
+}
@@ -0,0 +1,393 @@
+package smartpagelist
+
+import "fmt"
+
+// ------------------------------ 删除操作 ------------------------------
+//
+// PopBack/PopFront 在两种 IndexMode 下都是摊销 O(1)：PopBack 直接截断
+// 物理末尾，PopFront 通过推进 FirstPageNumber/HeadOffset 跳过头部，均不
+// 需要重排中间元素。RemoveAt 按 IndexMode 分流：Dense 模式下重建整个
+// 物理布局（O(n)），Sparse 模式下仅将槽位标记为墓碑并计入 FreeSlots
+// （O(1)）；读取时 Get 靠 meta.PageCounts 这张跳表在内存中定位目标页，
+// 同样是 O(1) 次状态读取（见 list.go locatePhysical 与 freelist.go pageLiveCount）。
+//
+// 每个操作都先在内存中算出完整的 writes（键 -> 新状态值，nil 表示删除）
+// 而不直接调用 store.PutState，最终统一交给 commitWrites 一次性提交
+// （store 实现 BatchStateStore 时只触发一次 PutStateBatch 调用）。这样
+// IndexedList 等需要把主列表的变更与若干二级索引的变更合并为同一次
+// 提交的上层场景，可以直接复用 stagePopBack/stagePopFront/stageRemoveAt
+// 算出的 writes，再与自己的 writes 合并后一起提交。
+//
+// 注意：Tx.Set/Tx.PushBack 的定位逻辑未作调整，仍假设列表从未调用过
+// PopFront/RemoveAt（即 FirstPageNumber/HeadOffset/FreeSlots 始终为零值），
+// 一旦不满足就会返回 ErrTxUnsupportedAfterSkew。随机访问场景请使用 Get
+// 或 Range，追加/随机写场景请改用 List.PushBack/RemoveAt（不经过 Tx）；
+// Cursor/GetRange 已经感知头部跳过与墓碑过滤。
+
+// PopBack 弹出并返回列表末尾元素
+func (l *List[T]) PopBack() (T, error) {
+	var zero T
+
+	meta, err := l.getMeta()
+	if err != nil {
+		return zero, err
+	}
+
+	writes, value, err := l.stagePopBack(meta)
+	if err != nil {
+		return zero, err
+	}
+	if err := commitWrites(l.store, l.key, writes); err != nil {
+		return zero, err
+	}
+	return value, nil
+}
+
+// stagePopBack 计算 PopBack 对应的状态变更（meta 在原地被更新），但不执行
+// 任何写入，返回待提交的 writes
+func (l *List[T]) stagePopBack(meta *listMeta) (map[string][]byte, T, error) {
+	var zero T
+	if meta.TotalCount == 0 {
+		return nil, zero, ErrIndexOutOfRange
+	}
+
+	writes := make(map[string][]byte)
+
+	for {
+		pageNumber := meta.LastPageNumber
+		pageKey := l.buildPageKey(pageNumber)
+		pageData, err := l.store.GetState(pageKey)
+		if err != nil {
+			return nil, zero, fmt.Errorf("get page state failed [key:%s]: %w", pageKey, err)
+		}
+
+		rawElements, err := l.framer.DecodePage(pageData)
+		if err != nil {
+			return nil, zero, fmt.Errorf("decode page envelope failed [key:%s]: %w", pageKey, err)
+		}
+		if len(rawElements) == 0 {
+			return nil, zero, fmt.Errorf("data inconsistency detected [key:%s, page:%d]: page is empty but listed as last page", l.key, pageNumber)
+		}
+
+		tailOffset := len(rawElements) - 1
+		raw := rawElements[tailOffset]
+		tailWasFree := l.indexMode == Sparse && isFreeSlot(meta, pageNumber, tailOffset)
+		rawElements = rawElements[:tailOffset]
+
+		if len(rawElements) == 0 {
+			writes[pageKey] = nil
+			meta.LastPageNumber--
+			delete(meta.PageCounts, pageNumber)
+		} else {
+			newPageData, err := l.framer.EncodePage(rawElements)
+			if err != nil {
+				return nil, zero, fmt.Errorf("encode page envelope failed [key:%s]: %w", pageKey, err)
+			}
+			writes[pageKey] = newPageData
+			l.setPageCount(meta, pageNumber, len(rawElements))
+		}
+
+		if tailWasFree {
+			// 末尾恰好是一个墓碑槽位，物理截断后它已不存在，清理对应的 FreeSlots 记录后继续向前找真正的末尾元素
+			removeFreeSlot(meta, pageNumber, tailOffset)
+			if meta.LastPageNumber == 0 {
+				return nil, zero, fmt.Errorf("data inconsistency detected [key:%s]: ran out of pages while popping back", l.key)
+			}
+			continue
+		}
+
+		value, err := l.codec.Decode(raw)
+		if err != nil {
+			return nil, zero, fmt.Errorf("decode value failed [key:%s, page:%d, offset:%d]: %w", l.key, pageNumber, tailOffset, err)
+		}
+
+		meta.TotalCount--
+		l.resetIfDrained(meta, writes)
+		metaBytes, err := l.encodeMeta(meta)
+		if err != nil {
+			return nil, zero, err
+		}
+		writes[l.metaKey()] = metaBytes
+		return writes, value, nil
+	}
+}
+
+// resetIfDrained 在列表被弹空后删除仍残留已消费头部槽位或墓碑的页，并清零
+// 头部跳过与 freelist 状态，使下一次 PushBack 重新从空的第 1 页开始，而不是
+// 沿用已不存在的旧页码或追加在残留槽位之后
+func (l *List[T]) resetIfDrained(meta *listMeta, writes map[string][]byte) {
+	if meta.TotalCount != 0 {
+		return
+	}
+	first := meta.FirstPageNumber
+	if first == 0 {
+		first = 1
+	}
+	for page := first; page <= meta.LastPageNumber; page++ {
+		writes[l.buildPageKey(page)] = nil
+	}
+	meta.LastPageNumber = 0
+	meta.FirstPageNumber = 0
+	meta.HeadOffset = 0
+	meta.FreeSlots = nil
+}
+
+// PopFront 弹出并返回列表起始元素，通过推进头部跳过位置实现，不重排后续元素
+func (l *List[T]) PopFront() (T, error) {
+	var zero T
+
+	meta, err := l.getMeta()
+	if err != nil {
+		return zero, err
+	}
+
+	writes, value, err := l.stagePopFront(meta)
+	if err != nil {
+		return zero, err
+	}
+	if err := commitWrites(l.store, l.key, writes); err != nil {
+		return zero, err
+	}
+	return value, nil
+}
+
+// stagePopFront 计算 PopFront 对应的状态变更（meta 在原地被更新），但不
+// 执行任何写入，返回待提交的 writes
+func (l *List[T]) stagePopFront(meta *listMeta) (map[string][]byte, T, error) {
+	var zero T
+	if meta.TotalCount == 0 {
+		return nil, zero, ErrIndexOutOfRange
+	}
+
+	writes := make(map[string][]byte)
+
+	first := meta.FirstPageNumber
+	if first == 0 {
+		first = 1
+	}
+	headOffset := meta.HeadOffset
+
+	for {
+		pageKey := l.buildPageKey(first)
+		pageData, err := l.store.GetState(pageKey)
+		if err != nil {
+			return nil, zero, fmt.Errorf("get page state failed [key:%s]: %w", pageKey, err)
+		}
+
+		rawElements, err := l.framer.DecodePage(pageData)
+		if err != nil {
+			return nil, zero, fmt.Errorf("decode page envelope failed [key:%s]: %w", pageKey, err)
+		}
+		if headOffset >= len(rawElements) {
+			// 该页已被消费殆尽（理论上在上一次弹出时就该滚动到下一页），
+			// 直接回收并前进
+			writes[pageKey] = nil
+			delete(meta.PageCounts, first)
+			first++
+			headOffset = 0
+			continue
+		}
+
+		consumedPage := first
+		consumedOffset := headOffset
+		raw := rawElements[consumedOffset]
+		wasFree := l.indexMode == Sparse && isFreeSlot(meta, consumedPage, consumedOffset)
+		headOffset++
+		if headOffset >= len(rawElements) {
+			writes[pageKey] = nil
+			delete(meta.PageCounts, first)
+			first++
+			headOffset = 0
+		}
+
+		if wasFree {
+			removeFreeSlot(meta, consumedPage, consumedOffset)
+			continue
+		}
+
+		value, err := l.codec.Decode(raw)
+		if err != nil {
+			return nil, zero, fmt.Errorf("decode value failed [key:%s, page:%d, offset:%d]: %w", l.key, consumedPage, consumedOffset, err)
+		}
+
+		meta.FirstPageNumber = first
+		meta.HeadOffset = headOffset
+		meta.TotalCount--
+		l.resetIfDrained(meta, writes)
+		metaBytes, err := l.encodeMeta(meta)
+		if err != nil {
+			return nil, zero, err
+		}
+		writes[l.metaKey()] = metaBytes
+		return writes, value, nil
+	}
+}
+
+// RemoveAt 删除指定索引处的元素
+//   - Dense 模式：重建整个物理布局以保持紧凑，开销 O(n)
+//   - Sparse 模式：仅将槽位标记为墓碑并计入 FreeSlots，开销 O(1)
+//
+// 首尾元素总是分别退化为 PopFront/PopBack，不受 IndexMode 影响
+func (l *List[T]) RemoveAt(index int) error {
+	meta, err := l.getMeta()
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= meta.TotalCount {
+		return ErrIndexOutOfRange
+	}
+
+	writes, err := l.stageRemoveAt(meta, index)
+	if err != nil {
+		return err
+	}
+	return commitWrites(l.store, l.key, writes)
+}
+
+// stageRemoveAt 计算 RemoveAt(index) 对应的状态变更（meta 在原地被更新），
+// 但不执行任何写入，返回待提交的 writes；调用方需保证 index 已经过边界校验
+func (l *List[T]) stageRemoveAt(meta *listMeta, index int) (map[string][]byte, error) {
+	if index == 0 {
+		writes, _, err := l.stagePopFront(meta)
+		return writes, err
+	}
+	if index == meta.TotalCount-1 {
+		writes, _, err := l.stagePopBack(meta)
+		return writes, err
+	}
+
+	if l.indexMode == Sparse {
+		return l.stageRemoveAtSparse(meta, index)
+	}
+	return l.stageRemoveAtDense(meta, index)
+}
+
+// stageRemoveAtDense 收集除目标索引外的全部存活元素，并算出重新写回紧凑
+// 布局所需的 writes
+//
+// 这里逐个调用 Get 收集存活元素，Get 经由 locatePhysical 计入
+// FirstPageNumber/HeadOffset，列表此前执行过 PopFront 时同样定位正确
+func (l *List[T]) stageRemoveAtDense(meta *listMeta, index int) (map[string][]byte, error) {
+	values := make([]T, 0, meta.TotalCount-1)
+	for i := 0; i < meta.TotalCount; i++ {
+		if i == index {
+			continue
+		}
+		value, err := l.Get(i)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+
+	return l.stageRebuild(meta, values)
+}
+
+// stageRebuild 计算把 values 作为列表的全部内容、从第 1 页开始重新打包
+// 所需的全部 writes（含清理重建之前遗留的旧页与更新 meta），但不执行
+// 任何写入；meta 在原地被更新为重建后的状态
+//
+// 除了供 stageRemoveAtDense 做 Dense 模式的整体重排外，也供 IndexedList
+// 在其它索引的倒排列表因主列表位置整体前移而需要重建时复用
+func (l *List[T]) stageRebuild(meta *listMeta, values []T) (map[string][]byte, error) {
+	writes := make(map[string][]byte)
+	oldLastPage := meta.LastPageNumber
+
+	// 重建会重新从第 1 页开始编号物理页，旧的 PageCounts 跳表整体失效
+	meta.PageCounts = nil
+
+	newLastPage := 0
+	for offset := 0; offset < len(values); offset += l.pageSize {
+		pageNumber := offset/l.pageSize + 1
+		end := offset + l.pageSize
+		if end > len(values) {
+			end = len(values)
+		}
+
+		encoded := make([][]byte, end-offset)
+		for i, value := range values[offset:end] {
+			e, err := l.codec.Encode(value)
+			if err != nil {
+				return nil, fmt.Errorf("encode value failed: %w", err)
+			}
+			encoded[i] = e
+		}
+		pageData, err := l.framer.EncodePage(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("encode page envelope failed [page:%d]: %w", pageNumber, err)
+		}
+		writes[l.buildPageKey(pageNumber)] = pageData
+		l.setPageCount(meta, pageNumber, end-offset)
+		newLastPage = pageNumber
+	}
+
+	// 清理重建之前遗留的、已不再使用的旧页
+	for page := newLastPage + 1; page <= oldLastPage; page++ {
+		writes[l.buildPageKey(page)] = nil
+	}
+
+	meta.LastPageNumber = newLastPage
+	meta.TotalCount = len(values)
+	meta.FirstPageNumber = 0
+	meta.HeadOffset = 0
+	meta.FreeSlots = nil
+	metaBytes, err := l.encodeMeta(meta)
+	if err != nil {
+		return nil, err
+	}
+	writes[l.metaKey()] = metaBytes
+	return writes, nil
+}
+
+// stageRemoveAtSparse 将目标索引处的槽位记入 FreeSlots 供复用
+//
+// 由于墓碑状态完全由 FreeSlots 维护（见 isFreeSlot），这里不需要改写
+// 页内容本身，只需要定位到目标槽位并更新元数据；目标页由 locatePhysical
+// 靠 PageCounts 跳表在内存中定出，只需读取这一页，因此是 O(1) 次状态读取
+func (l *List[T]) stageRemoveAtSparse(meta *listMeta, index int) (map[string][]byte, error) {
+	first := meta.FirstPageNumber
+	if first == 0 {
+		first = 1
+	}
+	startPage, remaining, err := l.locatePhysical(meta, index)
+	if err != nil {
+		return nil, err
+	}
+
+	for page := startPage; page <= meta.LastPageNumber; page++ {
+		pageKey := l.buildPageKey(page)
+		pageData, err := l.store.GetState(pageKey)
+		if err != nil {
+			return nil, fmt.Errorf("get page state failed [key:%s]: %w", pageKey, err)
+		}
+		rawElements, err := l.framer.DecodePage(pageData)
+		if err != nil {
+			return nil, fmt.Errorf("decode page envelope failed [key:%s]: %w", pageKey, err)
+		}
+
+		skip := 0
+		if page == first {
+			skip = meta.HeadOffset
+		}
+
+		for i := skip; i < len(rawElements); i++ {
+			if isFreeSlot(meta, page, i) {
+				continue
+			}
+			if remaining > 0 {
+				remaining--
+				continue
+			}
+
+			meta.FreeSlots = append(meta.FreeSlots, freeSlot{Page: page, Offset: i})
+			meta.TotalCount--
+			metaBytes, err := l.encodeMeta(meta)
+			if err != nil {
+				return nil, err
+			}
+			return map[string][]byte{l.metaKey(): metaBytes}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("data inconsistency detected [key:%s]: index %d not found despite totalCount=%d", l.key, index, meta.TotalCount)
+}
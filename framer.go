@@ -0,0 +1,101 @@
+package smartpagelist
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// PageFramer 定义单页内多个已编码元素如何打包进一个状态值（页封套格式）
+// 不同 Codec 产出的字节是否为合法 JSON 片段，决定了页应选用哪种 PageFramer：
+//   - JSONArrayFramer: 要求每个元素都是合法 JSON（如 JSONCodec 的输出），
+//     拼接结果与历史上 json.Marshal([]T) 的格式逐字节兼容
+//   - LengthPrefixedFramer: 不对元素内容做任何假设，适用于 GobCodec、
+//     ProtoCodec、RawBytesCodec 等不产出 JSON 的编解码器
+type PageFramer interface {
+	// EncodePage 将一页内按顺序排列的已编码元素打包为单个状态值
+	EncodePage(elements [][]byte) ([]byte, error)
+	// DecodePage 将状态值还原为按顺序排列的已编码元素
+	DecodePage(data []byte) ([][]byte, error)
+}
+
+// JSONArrayFramer 以 JSON 数组形式打包页内元素，是默认的 PageFramer 实现
+// 当元素本身就是合法 JSON 片段时（如 JSONCodec 的输出），其打包结果与早期
+// 版本中直接 json.Marshal([]string) 产出的格式完全一致，保证页存储格式兼容
+type JSONArrayFramer struct{}
+
+func (JSONArrayFramer) EncodePage(elements [][]byte) ([]byte, error) {
+	if len(elements) == 0 {
+		return []byte("[]"), nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, elem := range elements {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(elem)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+func (JSONArrayFramer) DecodePage(data []byte) ([][]byte, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("unmarshal page envelope failed: %w", err)
+	}
+
+	elements := make([][]byte, len(raw))
+	for i, r := range raw {
+		elements[i] = []byte(r)
+	}
+	return elements, nil
+}
+
+// LengthPrefixedFramer 以 4 字节大端长度前缀 + 内容的方式打包页内元素，
+// 不要求元素内容是合法 JSON，适用于 GobCodec / ProtoCodec / RawBytesCodec
+type LengthPrefixedFramer struct{}
+
+func (LengthPrefixedFramer) EncodePage(elements [][]byte) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, elem := range elements {
+		if err := binary.Write(&buf, binary.BigEndian, uint32(len(elem))); err != nil {
+			return nil, fmt.Errorf("write element length failed: %w", err)
+		}
+		buf.Write(elem)
+	}
+	return buf.Bytes(), nil
+}
+
+func (LengthPrefixedFramer) DecodePage(data []byte) ([][]byte, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var elements [][]byte
+	r := bytes.NewReader(data)
+	for {
+		var length uint32
+		if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("read element length failed: %w", err)
+		}
+
+		elem := make([]byte, length)
+		if _, err := io.ReadFull(r, elem); err != nil {
+			return nil, fmt.Errorf("read element content failed: %w", err)
+		}
+		elements = append(elements, elem)
+	}
+	return elements, nil
+}
@@ -0,0 +1,133 @@
+package smartpagelist
+
+import "fmt"
+
+// IndexMode 决定 RemoveAt 在列表产生空洞后如何维持 index 与物理存储的对应关系
+type IndexMode int
+
+const (
+	// Dense 是默认模式：RemoveAt 立即重排物理存储使其保持紧凑，
+	// 代价是最坏情况下 O(n) 的删除开销，换来 O(1) 的读取开销
+	Dense IndexMode = iota
+	// Sparse 模式下 RemoveAt 只将槽位记入 freelist，不重排后续元素，
+	// 删除是 O(1) 的。Get 按逻辑索引定位目标页时，靠 meta.PageCounts 这张
+	// 随页写入同步维护的跳表在内存中累加各页的存活元素数量，因此只需要
+	// 对定位到的目标页发起一次真正的状态读取（O(1) 次状态读取），不随墓碑
+	// 密度或列表长度退化成逐页扫描。
+	//
+	// 权衡：PushBack 会优先复用 freelist 中的墓碑槽位而不是在物理末尾
+	// 追加，这意味着被复用槽位的新元素在物理/逻辑遍历顺序上会出现在
+	// 它所复用的那个槽位原来的位置，而不是列表的逻辑末尾——Sparse 模式
+	// 是为换取更紧凑的存储空间而牺牲严格的追加顺序
+	Sparse
+)
+
+// freeSlot 记录一个因 RemoveAt 产生的、可供下次 PushBack 复用的墓碑槽位
+type freeSlot struct {
+	Page   int `json:"page"`   // 槽位所在的物理页码
+	Offset int `json:"offset"` // 槽位在该页原始元素数组中的位置
+}
+
+// WithIndexMode 指定列表的删除语义，默认为 Dense
+//
+// 注意：PopFront 使用的头部跳过机制（FirstPageNumber/HeadOffset）在两种
+// 模式下都生效，Get/GetPage/Range/Cursor/GetRange 均已感知；但 Tx.Set 与
+// Tx.PushBack 仍假设列表从未发生过头部跳过或 Sparse 墓碑，一旦不满足就
+// 会报 ErrTxUnsupportedAfterSkew，而不是悄悄写坏数据——此时请改用
+// List.PushBack/RemoveAt（不经过 Tx）
+func WithIndexMode[T any](mode IndexMode) Option[T] {
+	return func(l *List[T]) {
+		l.indexMode = mode
+	}
+}
+
+// isFreeSlot 判断指定物理槽位是否已被 RemoveAt 标记为墓碑
+//
+// 墓碑状态完全由 meta.FreeSlots 维护，不会修改 Codec/PageFramer 产出的
+// 字节内容——这样 Sparse 模式对任意 Codec/PageFramer 组合都是安全的，
+// 不要求元素编码结果本身能够承载额外的存活标记字节
+func isFreeSlot(meta *listMeta, page, offset int) bool {
+	for _, fs := range meta.FreeSlots {
+		if fs.Page == page && fs.Offset == offset {
+			return true
+		}
+	}
+	return false
+}
+
+// freeSlotsOnPage 统计 FreeSlots 中落在指定物理页的墓碑数量
+func freeSlotsOnPage(meta *listMeta, page int) int {
+	count := 0
+	for _, fs := range meta.FreeSlots {
+		if fs.Page == page {
+			count++
+		}
+	}
+	return count
+}
+
+// pageLiveCount 在不读取该页实际内容的前提下，仅凭 meta 中记录的
+// PageCounts（页的原始元素数量）与 FreeSlots（墓碑位置）算出该页参与
+// 逻辑编号的存活元素数量。Sparse 模式下 locatePhysical 靠它在内存中
+// 跳过无需读取的页，把定位开销从逐页调用 GetPage 降到只读目标页这一次
+func (l *List[T]) pageLiveCount(meta *listMeta, page, first int) int {
+	raw, ok := meta.PageCounts[page]
+	if !ok && page < meta.LastPageNumber {
+		// 兼容 PageCounts 字段引入之前写入的页：除最后一页外，
+		// 页的原始长度总是 pageSize
+		raw = l.pageSize
+	}
+
+	live := raw - freeSlotsOnPage(meta, page)
+	if page == first {
+		live -= meta.HeadOffset
+	}
+	if live < 0 {
+		live = 0
+	}
+	return live
+}
+
+// removeFreeSlot 从 freelist 中剔除已经不再存在的槽位（页被物理截断时调用）
+func removeFreeSlot(meta *listMeta, page, offset int) {
+	filtered := meta.FreeSlots[:0]
+	for _, fs := range meta.FreeSlots {
+		if fs.Page == page && fs.Offset == offset {
+			continue
+		}
+		filtered = append(filtered, fs)
+	}
+	meta.FreeSlots = filtered
+}
+
+// writeRawSlot 将 value 写入指定物理槽位，用于复用 freelist 中记录的墓碑位置
+func (l *List[T]) writeRawSlot(pageNumber, offset int, value T) error {
+	pageKey := l.buildPageKey(pageNumber)
+	pageData, err := l.store.GetState(pageKey)
+	if err != nil {
+		return fmt.Errorf("get page state failed [key:%s]: %w", pageKey, err)
+	}
+
+	rawElements, err := l.framer.DecodePage(pageData)
+	if err != nil {
+		return fmt.Errorf("decode page envelope failed [key:%s]: %w", pageKey, err)
+	}
+	if offset < 0 || offset >= len(rawElements) {
+		return fmt.Errorf("data inconsistency detected [key:%s, page:%d, offset:%d]: page has %d items", l.key, pageNumber, offset, len(rawElements))
+	}
+
+	encoded, err := l.codec.Encode(value)
+	if err != nil {
+		return fmt.Errorf("encode value failed: %w", err)
+	}
+	rawElements[offset] = encoded
+
+	newPageData, err := l.framer.EncodePage(rawElements)
+	if err != nil {
+		return fmt.Errorf("encode page envelope failed [key:%s]: %w", pageKey, err)
+	}
+	if err := l.store.PutState(pageKey, newPageData); err != nil {
+		return fmt.Errorf("save page state failed [key:%s]: %w", pageKey, err)
+	}
+	return nil
+}
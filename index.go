@@ -0,0 +1,418 @@
+package smartpagelist
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrUniqueIndexViolation 表示写入的值在某个 Unique 索引键上与已有元素冲突
+var ErrUniqueIndexViolation = errors.New("unique index violation")
+
+// IndexSpec 描述附加在 IndexedList 上的一个二级索引
+//   - Name: 索引名，与主列表 key 共同构成该索引倒排列表的存储键前缀
+//   - Extract: 从元素中提取索引键，支持返回多个键（如标签字段），返回
+//     空切片表示该元素不参与此索引
+//   - Unique: 为 true 时要求每个键最多对应一个元素，冲突时 PushBack 返回
+//     包装了 ErrUniqueIndexViolation 的错误
+type IndexSpec[T any] struct {
+	Name    string
+	Extract func(T) []string
+	Unique  bool
+}
+
+// IndexedList 在 List[T] 之上附加若干二级索引
+//
+// 每个索引键对应一个独立的倒排列表（posting list，本身也是一个
+// List[int]，存储匹配元素在主列表中的逻辑位置），存储键为
+// "<主列表key>_idx_<索引名>_<索引键>"。这模仿了 Elasticsearch 等客户端
+// 暴露给 Go 使用者的 term-posting 模式，但完全基于 StateStore 实现，不
+// 引入任何外部依赖
+//
+// PushBack 会把主列表的追加与所有受影响索引的更新合并为同一次
+// commitWrites 调用。store 实现 BatchStateStore 时只触发一次
+// PutStateBatch，该批写入要么整体生效、要么在失败时不留下任何部分状态；
+// 否则 commitWrites 逐个 key 调用 PutState，不保证这批写入的原子性
+//
+// 注意：List[T] 的索引是逻辑位置而非元素的稳定标识，RemoveAt 之后所有
+// 后续元素的逻辑索引都会整体前移一位（与底层 List[T] 的既有行为一致，
+// 见 delete.go 顶部注释）。为了让查询结果保持正确，RemoveAt 会遍历每个
+// 索引已经出现过的全部键（通过 keysList 维护的键注册表），对其倒排列表
+// 中大于被删除位置的条目整体减一、等于被删除位置的条目直接去掉，开销
+// 正比于索引的倒排条目总数，而不只是被删除元素自身涉及的那几个键——
+// 这与 Dense IndexMode 下 RemoveAt 重排整个主列表物理布局的思路一致
+// （见 delete.go），用确定的 O(n) 开销换取任何时刻查询结果都正确，而不
+// 必在查询路径上做额外校验。
+//
+// 另外，IndexedList 只保证通过它自身的 PushBack/RemoveAt 发起的变更会
+// 同步维护索引；直接对内嵌的 *List[T] 调用 PopBack/PopFront/Begin 等方法
+// 会绕过索引维护，使索引变得陈旧。
+//
+// 注意：RemoveAt(0) 会让主列表产生 PopFront 式的头部跳过（见 delete.go
+// stagePopFront），此后 PushBack 内部用于追加主列表的 Tx.PushBack 会返回
+// ErrTxUnsupportedAfterSkew 而不是悄悄写坏数据或让索引指向读不出来的位置
+// ——这是预期行为，而不是遗留 bug。
+type IndexedList[T any] struct {
+	*List[T]
+	specs []IndexSpec[T]
+}
+
+// NewIndexedList 创建一个带二级索引的分页列表
+//   - listKey/pageSize/store/opts: 与 NewList 含义相同，用于构造主列表
+//   - specs: 随主列表一起维护的二级索引定义
+func NewIndexedList[T any](listKey string, pageSize int, store StateStore, specs []IndexSpec[T], opts ...Option[T]) *IndexedList[T] {
+	return &IndexedList[T]{
+		List:  NewList[T](listKey, pageSize, store, opts...),
+		specs: specs,
+	}
+}
+
+// spec 按名称查找索引定义
+func (il *IndexedList[T]) spec(name string) (IndexSpec[T], bool) {
+	for _, s := range il.specs {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return IndexSpec[T]{}, false
+}
+
+// postingList 返回指定索引名与索引键对应的倒排列表
+// 固定使用 Dense 模式：posting 条目本身不多，Dense 模式保证其内部顺序
+// 与追加顺序一致（严格递增），这是 QueryAND/QueryOR 做排序归并的前提
+func (il *IndexedList[T]) postingList(name, key string) *List[int] {
+	return NewList[int](fmt.Sprintf("%s_idx_%s_%s", il.key, name, key), il.pageSize, il.store)
+}
+
+// keysList 返回某个索引出现过的全部键的注册表（去重后的键集合），用于
+// RemoveAt 枚举该索引下所有需要重新编号的倒排列表
+func (il *IndexedList[T]) keysList(name string) *List[string] {
+	return NewList[string](fmt.Sprintf("%s_idx_%s_keys", il.key, name), il.pageSize, il.store)
+}
+
+// PushBack 追加元素到主列表末尾，并在同一次批量提交内更新所有受影响的
+// 二级索引；若某个 Unique 索引已存在同名键，整个操作（包括主列表的追加）
+// 都不会生效
+func (il *IndexedList[T]) PushBack(value T) error {
+	tx, err := il.Begin()
+	if err != nil {
+		return err
+	}
+	newIndex := tx.meta.TotalCount
+
+	var postingTxs []*Tx[int]
+	var keysTxs []*Tx[string]
+	for _, spec := range il.specs {
+		for _, key := range spec.Extract(value) {
+			pl := il.postingList(spec.Name, key)
+			ptx, err := pl.Begin()
+			if err != nil {
+				return err
+			}
+			if spec.Unique && ptx.meta.TotalCount > 0 {
+				return fmt.Errorf("%w: index %q key %q already has an entry", ErrUniqueIndexViolation, spec.Name, key)
+			}
+			if err := ptx.PushBack(newIndex); err != nil {
+				return err
+			}
+			postingTxs = append(postingTxs, ptx)
+
+			if ptx.meta.TotalCount == 1 {
+				// 这是该键第一次出现，登记进键注册表，供 RemoveAt 枚举
+				kl := il.keysList(spec.Name)
+				ktx, err := kl.Begin()
+				if err != nil {
+					return err
+				}
+				if err := ktx.PushBack(key); err != nil {
+					return err
+				}
+				keysTxs = append(keysTxs, ktx)
+			}
+		}
+	}
+
+	if err := tx.PushBack(value); err != nil {
+		return err
+	}
+
+	writes, err := tx.stagedWrites()
+	if err != nil {
+		return err
+	}
+	for _, ptx := range postingTxs {
+		pw, err := ptx.stagedWrites()
+		if err != nil {
+			return err
+		}
+		for k, v := range pw {
+			writes[k] = v
+		}
+	}
+	for _, ktx := range keysTxs {
+		kw, err := ktx.stagedWrites()
+		if err != nil {
+			return err
+		}
+		for k, v := range kw {
+			writes[k] = v
+		}
+	}
+
+	tx.closed = true
+	for _, ptx := range postingTxs {
+		ptx.closed = true
+	}
+	for _, ktx := range keysTxs {
+		ktx.closed = true
+	}
+	return commitWrites(il.store, il.key, writes)
+}
+
+// RemoveAt 删除主列表中指定索引处的元素，并在同一次批量提交内更新所有
+// 索引：对每个索引已经登记过的每个键对应的倒排列表，把值等于 index 的
+// 条目去掉，把值大于 index 的条目整体减一（详见类型注释）
+func (il *IndexedList[T]) RemoveAt(index int) error {
+	meta, err := il.getMeta()
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= meta.TotalCount {
+		return ErrIndexOutOfRange
+	}
+
+	writes, err := il.stageRemoveAt(meta, index)
+	if err != nil {
+		return err
+	}
+
+	for _, spec := range il.specs {
+		kl := il.keysList(spec.Name)
+		keyCount, err := kl.Length()
+		if err != nil {
+			return err
+		}
+		if keyCount == 0 {
+			continue
+		}
+
+		var keys []string
+		if err := kl.Range(0, -1, func(_ int, key string) error {
+			keys = append(keys, key)
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			pl := il.postingList(spec.Name, key)
+			plen, err := pl.Length()
+			if err != nil {
+				return err
+			}
+			if plen == 0 {
+				continue
+			}
+
+			values := make([]int, 0, plen)
+			changed := false
+			for pos := 0; pos < plen; pos++ {
+				v, err := pl.Get(pos)
+				if err != nil {
+					return err
+				}
+				switch {
+				case v == index:
+					changed = true // 被删除元素自身的倒排记录，丢弃
+				case v > index:
+					values = append(values, v-1)
+					changed = true
+				default:
+					values = append(values, v)
+				}
+			}
+			if !changed {
+				continue
+			}
+
+			pmeta, err := pl.getMeta()
+			if err != nil {
+				return err
+			}
+			pw, err := pl.stageRebuild(pmeta, values)
+			if err != nil {
+				return err
+			}
+			for k, v := range pw {
+				writes[k] = v
+			}
+		}
+	}
+
+	return commitWrites(il.store, il.key, writes)
+}
+
+// QueryByIndex 返回主列表中索引键等于 key 的所有元素位置，按升序排列
+//
+// RemoveAt 已经保证了每个索引的倒排列表中的位置始终对应当前的主列表
+// 布局（见类型注释），这里不需要再做自愈式校验，直接返回倒排列表内容
+func (il *IndexedList[T]) QueryByIndex(name, key string) ([]int, error) {
+	if _, ok := il.spec(name); !ok {
+		return nil, fmt.Errorf("index %q not found", name)
+	}
+
+	pl := il.postingList(name, key)
+	var results []int
+	if err := pl.Range(0, -1, func(_ int, candidate int) error {
+		results = append(results, candidate)
+		return nil
+	}); err != nil {
+		if errors.Is(err, ErrIndexOutOfRange) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return results, nil
+}
+
+// indexQueryToken 是 QueryByIndexPaged 返回的分页令牌，语义上只是对
+// QueryByIndex 结果做了一次偏移切片
+type indexQueryToken struct {
+	Offset int `json:"offset"`
+}
+
+// QueryByIndexPaged 与 QueryByIndex 等价，但每次只返回最多 limit 条结果，
+// 并返回指向下一页起始位置的 nextToken；pageToken 为空时从头开始，
+// nextToken 为空表示已经到达结果集末尾
+func (il *IndexedList[T]) QueryByIndexPaged(name, key string, pageToken []byte, limit int) ([]int, []byte, error) {
+	if limit <= 0 {
+		return nil, nil, fmt.Errorf("limit must be > 0")
+	}
+
+	all, err := il.QueryByIndex(name, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	offset := 0
+	if len(pageToken) > 0 {
+		var token indexQueryToken
+		if err := json.Unmarshal(pageToken, &token); err != nil {
+			return nil, nil, fmt.Errorf("unmarshal index query token failed: %w", err)
+		}
+		offset = token.Offset
+	}
+	if offset < 0 || offset > len(all) {
+		return nil, nil, ErrIndexOutOfRange
+	}
+
+	end := offset + limit
+	if end > len(all) {
+		end = len(all)
+	}
+	page := all[offset:end]
+
+	if end >= len(all) {
+		return page, nil, nil
+	}
+
+	nextToken, err := json.Marshal(indexQueryToken{Offset: end})
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshal index query token failed: %w", err)
+	}
+	return page, nextToken, nil
+}
+
+// IndexQuery 描述一次单索引等值查询，用于 QueryAND/QueryOR 的布尔组合
+type IndexQuery struct {
+	Name string
+	Key  string
+}
+
+// QueryAND 返回同时匹配所有给定索引查询的元素位置（升序），通过对各自
+// 有序的倒排结果做排序归并求交集实现
+func (il *IndexedList[T]) QueryAND(queries ...IndexQuery) ([]int, error) {
+	if len(queries) == 0 {
+		return nil, nil
+	}
+
+	result, err := il.QueryByIndex(queries[0].Name, queries[0].Key)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, q := range queries[1:] {
+		if len(result) == 0 {
+			break
+		}
+		next, err := il.QueryByIndex(q.Name, q.Key)
+		if err != nil {
+			return nil, err
+		}
+		result = intersectSorted(result, next)
+	}
+	return result, nil
+}
+
+// QueryOR 返回匹配任一给定索引查询的元素位置（升序、去重），通过对各自
+// 有序的倒排结果做排序归并求并集实现
+func (il *IndexedList[T]) QueryOR(queries ...IndexQuery) ([]int, error) {
+	var result []int
+	for _, q := range queries {
+		next, err := il.QueryByIndex(q.Name, q.Key)
+		if err != nil {
+			return nil, err
+		}
+		result = unionSorted(result, next)
+	}
+	return result, nil
+}
+
+// intersectSorted 对两个升序且无重复的切片做排序归并求交集
+func intersectSorted(a, b []int) []int {
+	result := make([]int, 0, minInt(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			result = append(result, a[i])
+			i++
+			j++
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	return result
+}
+
+// unionSorted 对两个升序且无重复的切片做排序归并求并集
+func unionSorted(a, b []int) []int {
+	result := make([]int, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			result = append(result, a[i])
+			i++
+			j++
+		case a[i] < b[j]:
+			result = append(result, a[i])
+			i++
+		default:
+			result = append(result, b[j])
+			j++
+		}
+	}
+	result = append(result, a[i:]...)
+	result = append(result, b[j:]...)
+	return result
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -13,10 +13,15 @@ type StateStore interface {
 }
 
 // List 实现基于状态数据库的分页列表，适用于智能合约场景
-type List struct {
-	key      string     // 列表唯一标识
-	pageSize int        // 每页最大元素数量
-	store    StateStore // 状态存储接口
+// T 是元素类型，元素与字节之间的转换由 codec 负责，页内多个元素如何
+// 打包进一个状态值由 framer 负责，二者均可通过 Option 在构造时替换
+type List[T any] struct {
+	key       string     // 列表唯一标识
+	pageSize  int        // 每页最大元素数量
+	store     StateStore // 状态存储接口
+	codec     Codec[T]   // 元素编解码器
+	framer    PageFramer // 页封套打包格式
+	indexMode IndexMode  // RemoveAt 的空洞处理策略，默认 Dense
 }
 
 // 错误类型定义
@@ -31,19 +36,45 @@ const (
 
 // ------------------------------ 初始化方法 ------------------------------
 
+// Option 用于在构造 List[T] 时自定义编解码器、页封套格式等可选项
+type Option[T any] func(*List[T])
+
+// WithCodec 指定元素编解码器，默认使用 JSONCodec[T]
+func WithCodec[T any](codec Codec[T]) Option[T] {
+	return func(l *List[T]) {
+		l.codec = codec
+	}
+}
+
+// WithPageFramer 指定页封套打包格式，默认使用 JSONArrayFramer
+// 选用 GobCodec / ProtoCodec / RawBytesCodec 等非 JSON 编解码器时，
+// 应搭配 LengthPrefixedFramer 使用
+func WithPageFramer[T any](framer PageFramer) Option[T] {
+	return func(l *List[T]) {
+		l.framer = framer
+	}
+}
+
 // NewList 创建分页列表实例
 //   - listKey: 列表唯一标识，用于状态数据库中的键名前缀
 //   - pageSize: 每页元素数量（建议值：10~100，根据业务场景调整）
 //   - store: 状态存储实现（需由合约上下文注入）
-func NewList(listKey string, pageSize int, store StateStore) *List {
+//   - opts: 可选配置，如 WithCodec、WithPageFramer
+func NewList[T any](listKey string, pageSize int, store StateStore, opts ...Option[T]) *List[T] {
 	if pageSize <= 0 {
 		pageSize = DefaultPageSize
 	}
-	return &List{
+	l := &List[T]{
 		key:      listKey,
 		pageSize: pageSize,
 		store:    store,
+		codec:    JSONCodec[T]{},
+		framer:   JSONArrayFramer{},
+	}
+	for _, opt := range opts {
+		opt(l)
 	}
+	return l
 }
 
 // ------------------------------ 元数据管理 ------------------------------
@@ -52,15 +83,33 @@ func NewList(listKey string, pageSize int, store StateStore) *List {
 type listMeta struct {
 	LastPageNumber int `json:"lastPageNumber"` // 最新页码（从1开始）
 	TotalCount     int `json:"totalCount"`     // 列表总元素数量
+
+	// FirstPageNumber/HeadOffset 记录 PopFront 产生的头部跳过位置：
+	// 列表的第一个有效元素位于 FirstPageNumber 页的第 HeadOffset 个原始
+	// 槽位之后。二者均为零值时表示列表从未执行过 PopFront，行为与旧版本
+	// 完全一致
+	FirstPageNumber int `json:"firstPageNumber,omitempty"`
+	HeadOffset      int `json:"headOffset,omitempty"`
+
+	// FreeSlots 记录 Sparse 模式下 RemoveAt 产生的墓碑槽位，供下一次
+	// PushBack 优先复用，避免列表无限膨胀
+	FreeSlots []freeSlot `json:"freeSlots,omitempty"`
+
+	// PageCounts 记录每个物理页当前的原始元素数组长度（包含尚未被过滤掉
+	// 的墓碑，即 RemoveAt 之前的长度）。这是 Sparse 模式下 Get/Cursor 用来
+	// 按逻辑索引定位目标页的跳表：配合 FreeSlots 即可在不实际读取任何中间
+	// 页的前提下，仅凭 meta 本身算出每页的存活元素数量，从而把定位开销从
+	// 逐页调用 GetPage 降到只读目标页这一次状态查询
+	PageCounts map[int]int `json:"pageCounts,omitempty"`
 }
 
 // metaKey 生成元数据存储键（格式: "listKey_meta"）
-func (l *List) metaKey() string {
+func (l *List[T]) metaKey() string {
 	return fmt.Sprintf("%s_meta", l.key)
 }
 
 // getMeta 从状态数据库读取元数据
-func (l *List) getMeta() (*listMeta, error) {
+func (l *List[T]) getMeta() (*listMeta, error) {
 	metaBytes, err := l.store.GetState(l.metaKey())
 	if err != nil {
 		return nil, fmt.Errorf("get meta state failed [key:%s]: %w", l.metaKey(), err)
@@ -78,11 +127,31 @@ func (l *List) getMeta() (*listMeta, error) {
 	return &meta, nil
 }
 
-// saveMeta 将元数据写入状态数据库
-func (l *List) saveMeta(meta *listMeta) error {
+// setPageCount 记录 page 当前的原始元素数组长度，供 Sparse 模式下的跳表
+// （PageCounts）使用；写入路径（PushBack/stageRebuild/stagePopBack）在
+// 页内容发生变化时都应调用它保持跳表与实际存储一致
+func (l *List[T]) setPageCount(meta *listMeta, page, count int) {
+	if meta.PageCounts == nil {
+		meta.PageCounts = make(map[int]int)
+	}
+	meta.PageCounts[page] = count
+}
+
+// encodeMeta 序列化元数据但不执行写入，供需要先把多个键值对暂存成 writes
+// 再统一提交的场景（stagePopBack/stagePopFront/stageRemoveAt、IndexedList）复用
+func (l *List[T]) encodeMeta(meta *listMeta) ([]byte, error) {
 	metaBytes, err := json.Marshal(meta)
 	if err != nil {
-		return fmt.Errorf("marshal meta failed: %w", err)
+		return nil, fmt.Errorf("marshal meta failed: %w", err)
+	}
+	return metaBytes, nil
+}
+
+// saveMeta 将元数据写入状态数据库
+func (l *List[T]) saveMeta(meta *listMeta) error {
+	metaBytes, err := l.encodeMeta(meta)
+	if err != nil {
+		return err
 	}
 
 	if err := l.store.PutState(l.metaKey(), metaBytes); err != nil {
@@ -95,37 +164,58 @@ func (l *List) saveMeta(meta *listMeta) error {
 
 // PushBack 追加元素到列表末尾
 // 注意：此操作会修改元数据和分页数据，确保原子性由底层状态存储保证
-func (l *List) PushBack(value string) error {
+func (l *List[T]) PushBack(value T) error {
 	meta, err := l.getMeta()
 	if err != nil {
 		return err
 	}
 
-	// 计算目标页码（新增页或当前页）
+	// Sparse 模式下优先复用 RemoveAt 留下的墓碑槽位，避免列表无限膨胀
+	if l.indexMode == Sparse && len(meta.FreeSlots) > 0 {
+		slot := meta.FreeSlots[len(meta.FreeSlots)-1]
+		meta.FreeSlots = meta.FreeSlots[:len(meta.FreeSlots)-1]
+		if err := l.writeRawSlot(slot.Page, slot.Offset, value); err != nil {
+			return err
+		}
+		meta.TotalCount++
+		return l.saveMeta(meta)
+	}
+
+	// 计算目标页码：若当前末页已写满（按实际物理槽位数判断，而非
+	// TotalCount%pageSize —— 二者在 FreeSlots 复用后可能不再一致），
+	// 则新开一页
 	targetPage := meta.LastPageNumber
-	if meta.TotalCount%l.pageSize == 0 {
-		targetPage++
+	if targetPage == 0 {
+		targetPage = 1
 	}
 
-	// 读取或初始化当前页数据
 	pageKey := l.buildPageKey(targetPage)
 	pageData, err := l.store.GetState(pageKey)
 	if err != nil {
 		return fmt.Errorf("get page state failed [key:%s]: %w", pageKey, err)
 	}
 
-	var values []string
-	if len(pageData) > 0 {
-		if err := json.Unmarshal(pageData, &values); err != nil {
-			return fmt.Errorf("unmarshal page data failed [key:%s]: %w", pageKey, err)
-		}
+	elements, err := l.framer.DecodePage(pageData)
+	if err != nil {
+		return fmt.Errorf("decode page envelope failed [key:%s]: %w", pageKey, err)
+	}
+
+	if len(elements) >= l.pageSize {
+		targetPage++
+		pageKey = l.buildPageKey(targetPage)
+		elements = nil
 	}
 
 	// 追加元素并保存
-	values = append(values, value)
-	newPageData, err := json.Marshal(values)
+	encoded, err := l.codec.Encode(value)
 	if err != nil {
-		return fmt.Errorf("marshal new page data failed: %w", err)
+		return fmt.Errorf("encode value failed: %w", err)
+	}
+	elements = append(elements, encoded)
+
+	newPageData, err := l.framer.EncodePage(elements)
+	if err != nil {
+		return fmt.Errorf("encode page envelope failed [key:%s]: %w", pageKey, err)
 	}
 
 	if err := l.store.PutState(pageKey, newPageData); err != nil {
@@ -135,13 +225,14 @@ func (l *List) PushBack(value string) error {
 	// 更新元数据
 	meta.LastPageNumber = targetPage
 	meta.TotalCount++
+	l.setPageCount(meta, targetPage, len(elements))
 	return l.saveMeta(meta)
 }
 
 // GetPage 获取指定页码的元素列表
 //   - pageNumber: 页码（从1开始）
 //   - 返回: 当前页元素列表，或 ErrPageNotFound
-func (l *List) GetPage(pageNumber int) ([]string, error) {
+func (l *List[T]) GetPage(pageNumber int) ([]T, error) {
 	if pageNumber < 1 {
 		return nil, errors.New("page number must be >= 1")
 	}
@@ -150,7 +241,11 @@ func (l *List) GetPage(pageNumber int) ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
-	if pageNumber > meta.LastPageNumber {
+	first := meta.FirstPageNumber
+	if first == 0 {
+		first = 1
+	}
+	if pageNumber < first || pageNumber > meta.LastPageNumber {
 		return nil, ErrPageNotFound
 	}
 
@@ -160,17 +255,124 @@ func (l *List) GetPage(pageNumber int) ([]string, error) {
 		return nil, fmt.Errorf("get page state failed [key:%s]: %w", pageKey, err)
 	}
 
-	var values []string
-	if err := json.Unmarshal(pageData, &values); err != nil {
-		return nil, fmt.Errorf("unmarshal page data failed [key:%s]: %w", pageKey, err)
+	elements, err := l.framer.DecodePage(pageData)
+	if err != nil {
+		return nil, fmt.Errorf("decode page envelope failed [key:%s]: %w", pageKey, err)
+	}
+
+	// 第一页的前 HeadOffset 个原始槽位已被 PopFront 消费，不再属于列表
+	skip := 0
+	if pageNumber == first {
+		skip = meta.HeadOffset
+		if skip > len(elements) {
+			skip = len(elements)
+		}
+	}
+
+	values := make([]T, 0, len(elements)-skip)
+	for i := skip; i < len(elements); i++ {
+		// Sparse 模式下已被 RemoveAt 标记为墓碑的槽位不参与编号
+		if l.indexMode == Sparse && isFreeSlot(meta, pageNumber, i) {
+			continue
+		}
+		value, err := l.codec.Decode(elements[i])
+		if err != nil {
+			return nil, fmt.Errorf("decode value failed [key:%s, offset:%d]: %w", pageKey, i, err)
+		}
+		values = append(values, value)
 	}
 	return values, nil
 }
 
 // ------------------------------ 查询方法 ------------------------------
 
+// Get 按索引获取元素
+//   - index: 元素索引（从0开始）
+//   - 返回: 索引越界时返回 ErrIndexOutOfRange
+func (l *List[T]) Get(index int) (T, error) {
+	var zero T
+
+	meta, err := l.getMeta()
+	if err != nil {
+		return zero, err
+	}
+	if index < 0 || index >= meta.TotalCount {
+		return zero, ErrIndexOutOfRange
+	}
+
+	pageNumber, offset, err := l.locatePhysical(meta, index)
+	if err != nil {
+		return zero, err
+	}
+
+	values, err := l.GetPage(pageNumber)
+	if err != nil {
+		return zero, err
+	}
+	if offset < 0 || offset >= len(values) {
+		return zero, fmt.Errorf("data inconsistency detected [key:%s, page:%d, offset:%d]: page has %d items", l.key, pageNumber, offset, len(values))
+	}
+	return values[offset], nil
+}
+
+// locatePhysical 将逻辑索引换算为物理页码，以及该索引在 GetPage 返回的
+// （已经过头部跳过与墓碑过滤的）切片中的偏移
+//
+// Get 与 Cursor.Value 共用这套定位规则，因此 Cursor 在列表发生过
+// PopFront（头部跳过）或 Sparse RemoveAt（墓碑）之后也能正确定位，而不是
+// 像旧版本那样假设 FirstPageNumber/HeadOffset 始终为零值
+//
+// Sparse 分支靠 meta.PageCounts/FreeSlots 这张跳表在内存中累加各页的存活
+// 元素数量来判断目标页，不需要为中间页各调用一次 GetPage；调用方随后只需
+// 对定位到的目标页发起一次真正的状态读取，这就是把 Get 的存储读取次数从
+// 逐页扫描的 O(页数) 降到 O(1) 的关键。PageCounts 中没有记录的页（在
+// PageCounts 引入前写入的页）除末页外按 pageSize 计数（见 pageLiveCount），
+// 末页则承接剩余的全部索引，因此这类旧页同样能正确定位
+func (l *List[T]) locatePhysical(meta *listMeta, index int) (pageNumber, offset int, err error) {
+	first := meta.FirstPageNumber
+	if first == 0 {
+		first = 1
+	}
+
+	if l.indexMode == Sparse {
+		remaining := index
+		for page := first; page <= meta.LastPageNumber; page++ {
+			live := l.pageLiveCount(meta, page, first)
+			if remaining < live || page == meta.LastPageNumber {
+				return page, remaining, nil
+			}
+			remaining -= live
+		}
+		return 0, 0, fmt.Errorf("data inconsistency detected [key:%s]: index %d not found despite totalCount=%d", l.key, index, meta.TotalCount)
+	}
+
+	// Dense 模式下列表本身不会产生墓碑，但 PopFront 可能已经跳过了
+	// 列表首页的若干原始槽位，需要把该偏移计入物理定位
+	physical := index + (first-1)*l.pageSize + meta.HeadOffset
+	pageNumber = physical/l.pageSize + 1
+	offset = physical % l.pageSize
+	if pageNumber == first {
+		offset -= meta.HeadOffset
+	}
+	return pageNumber, offset, nil
+}
+
+// GetLast 获取列表最后一个元素
+func (l *List[T]) GetLast() (T, error) {
+	var zero T
+
+	meta, err := l.getMeta()
+	if err != nil {
+		return zero, err
+	}
+	if meta.TotalCount == 0 {
+		return zero, ErrIndexOutOfRange
+	}
+	return l.Get(meta.TotalCount - 1)
+}
+
 // Length 获取列表总元素数量
-func (l *List) Length() (int, error) {
+func (l *List[T]) Length() (int, error) {
 	meta, err := l.getMeta()
 	if err != nil {
 		return 0, err
@@ -182,7 +384,7 @@ func (l *List) Length() (int, error) {
 //   - start: 起始索引（包含，从0开始）
 //   - end: 结束索引（不包含，-1表示列表末尾）
 //   - fn: 遍历回调函数（返回 error 可提前终止遍历）
-func (l *List) Range(start, end int, fn func(index int, value string) error) error {
+func (l *List[T]) Range(start, end int, fn func(index int, value T) error) error {
 	meta, err := l.getMeta()
 	if err != nil {
 		return err
@@ -196,24 +398,33 @@ func (l *List) Range(start, end int, fn func(index int, value string) error) err
 		return ErrIndexOutOfRange
 	}
 
-	currentPage := (start / l.pageSize) + 1
-	startPos := start % l.pageSize
+	// 逐页顺序扫描而非直接按 start/pageSize 跳转页码：Sparse 模式下
+	// 每页的墓碑会被 GetPage 过滤掉，各页的逻辑长度不再固定等于
+	// pageSize，唯有顺序累加逻辑索引才能同时兼容头部跳过
+	// （FirstPageNumber/HeadOffset）与墓碑过滤
+	first := meta.FirstPageNumber
+	if first == 0 {
+		first = 1
+	}
 
-	for index := start; index < end; {
-		values, err := l.GetPage(currentPage)
+	index := 0
+	for page := first; page <= meta.LastPageNumber && index < end; page++ {
+		values, err := l.GetPage(page)
 		if err != nil {
 			return err
 		}
 
-		for i := startPos; i < len(values) && index < end; i++ {
-			if err := fn(index, values[i]); err != nil {
-				return err
+		for _, value := range values {
+			if index >= end {
+				break
+			}
+			if index >= start {
+				if err := fn(index, value); err != nil {
+					return err
+				}
 			}
 			index++
 		}
-
-		currentPage++
-		startPos = 0 // 后续页从索引0开始
 	}
 
 	return nil
@@ -222,6 +433,11 @@ func (l *List) Range(start, end int, fn func(index int, value string) error) err
 // ------------------------------ 工具方法 ------------------------------
 
 // buildPageKey 生成分页数据存储键（格式: "listKey_page_页码"）
-func (l *List) buildPageKey(pageNumber int) string {
+func (l *List[T]) buildPageKey(pageNumber int) string {
 	return fmt.Sprintf("%s_page_%d", l.key, pageNumber)
 }
+
+// locate 将全局索引换算为页码（从1开始）与页内偏移量
+func (l *List[T]) locate(index int) (pageNumber, offset int) {
+	return index/l.pageSize + 1, index % l.pageSize
+}
@@ -41,11 +41,54 @@ func (m *mockStateStore) GetState(key string) ([]byte, error) {
 	return value, nil
 }
 
+// mockBatchStateStore 在 mockStateStore 基础上实现 BatchStateStore，
+// 并记录 PutState / PutStateBatch 的调用次数，便于验证 Tx 是否走了批量路径
+type mockBatchStateStore struct {
+	*mockStateStore
+	putCalls      int
+	putBatchCalls int
+}
+
+func NewMockBatchStateStore() *mockBatchStateStore {
+	return &mockBatchStateStore{mockStateStore: &mockStateStore{store: make(map[string][]byte)}}
+}
+
+func (m *mockBatchStateStore) PutState(key string, value []byte) error {
+	m.putCalls++
+	return m.mockStateStore.PutState(key, value)
+}
+
+func (m *mockBatchStateStore) PutStateBatch(kvs map[string][]byte) error {
+	m.putBatchCalls++
+	for key, value := range kvs {
+		if err := m.mockStateStore.PutState(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// countingStateStore 在 mockStateStore 基础上记录 GetState 的调用次数，
+// 用于验证 Sparse 模式下 Get 的定位是否真的只读取目标页，而不是逐页扫描
+type countingStateStore struct {
+	*mockStateStore
+	getCalls int
+}
+
+func newCountingStateStore() *countingStateStore {
+	return &countingStateStore{mockStateStore: &mockStateStore{store: make(map[string][]byte)}}
+}
+
+func (m *countingStateStore) GetState(key string) ([]byte, error) {
+	m.getCalls++
+	return m.mockStateStore.GetState(key)
+}
+
 // ------------------------------ 测试用例 ------------------------------
 
 func TestNewList(t *testing.T) {
 	store := NewMockStateStore()
-	list := NewList("test_list", 10, store)
+	list := NewList[string]("test_list", 10, store)
 
 	// 验证初始元数据
 	meta, err := list.getMeta()
@@ -60,7 +103,7 @@ func TestNewList(t *testing.T) {
 
 func TestPushBackSingleElement(t *testing.T) {
 	store := NewMockStateStore()
-	list := NewList("test_list", 10, store)
+	list := NewList[string]("test_list", 10, store)
 
 	// 添加元素
 	if err := list.PushBack("item1"); err != nil {
@@ -85,7 +128,7 @@ func TestPushBackSingleElement(t *testing.T) {
 
 func TestPushBackFullPage(t *testing.T) {
 	store := NewMockStateStore()
-	list := NewList("test_list", 3, store) // 每页3元素
+	list := NewList[string]("test_list", 3, store) // 每页3元素
 
 	// 填满第一页
 	for i := 0; i < 3; i++ {
@@ -123,7 +166,7 @@ func TestPushBackFullPage(t *testing.T) {
 
 func TestGetPageInvalidNumber(t *testing.T) {
 	store := NewMockStateStore()
-	list := NewList("test_list", 10, store)
+	list := NewList[string]("test_list", 10, store)
 
 	// 空列表查询
 	_, err := list.GetPage(1)
@@ -141,7 +184,7 @@ func TestGetPageInvalidNumber(t *testing.T) {
 
 func TestLength(t *testing.T) {
 	store := NewMockStateStore()
-	list := NewList("test_list", 3, store)
+	list := NewList[string]("test_list", 3, store)
 
 	// 空列表
 	if length, _ := list.Length(); length != 0 {
@@ -162,7 +205,7 @@ func TestLength(t *testing.T) {
 
 func TestRange(t *testing.T) {
 	store := NewMockStateStore()
-	list := NewList("test_list", 3, store)
+	list := NewList[string]("test_list", 3, store)
 
 	// 添加测试数据
 	for i := 0; i < 5; i++ {
@@ -202,7 +245,7 @@ func TestRange(t *testing.T) {
 func TestGetOperations(t *testing.T) {
 	// 初始化测试环境
 	store := NewMockStateStore()
-	list := NewList("test_list", 3, store) // 分页大小3
+	list := NewList[string]("test_list", 3, store) // 分页大小3
 
 	// 准备测试数据：4个元素，分2页（第1页3元素，第2页1元素）
 	testData := []string{"item1", "item2", "item3", "item4"}
@@ -258,7 +301,7 @@ func TestGetOperations(t *testing.T) {
 	t.Run("Get数据不一致用例", func(t *testing.T) {
 		// 手动构造损坏数据：第二页标记存在但实际为空
 		corruptStore := NewMockStateStore()
-		corruptList := NewList("corrupt_list", 3, corruptStore)
+		corruptList := NewList[string]("corrupt_list", 3, corruptStore)
 
 		// 设置元数据表示有1页3元素
 		corruptMeta := &listMeta{
@@ -295,7 +338,7 @@ func TestGetOperations(t *testing.T) {
 
 	t.Run("GetLast异常用例", func(t *testing.T) {
 		// 空列表用例
-		emptyList := NewList("empty_list", 3, store)
+		emptyList := NewList[string]("empty_list", 3, store)
 		_, err := emptyList.GetLast()
 		if !errors.Is(err, ErrIndexOutOfRange) {
 			t.Errorf("空列表预期 ErrIndexOutOfRange, 实际得到 %v", err)
@@ -303,7 +346,7 @@ func TestGetOperations(t *testing.T) {
 
 		// 构造最后一页为空的情况
 		badMetaStore := NewMockStateStore()
-		badList := NewList("bad_list", 3, badMetaStore)
+		badList := NewList[string]("bad_list", 3, badMetaStore)
 
 		// 元数据标记有1页但实际无数据
 		badMeta := &listMeta{
@@ -321,90 +364,1394 @@ func TestGetOperations(t *testing.T) {
 	})
 }
 
-// 压测参数配置
-const (
-	TotalItems    = 100000 // 总测试数据量
-	SmallPageSize = 10     // 小分页配置
-	LargePageSize = 1000   // 大分页配置
-	SamplePoints  = 100    // 采样点数量
-)
+func TestTxCommit(t *testing.T) {
+	store := NewMockStateStore()
+	list := NewList[string]("test_list", 3, store)
 
-// 初始化测试列表
-func initList(pageSize int) (*List, StateStore) {
+	tx, err := list.Begin()
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		if err := tx.PushBack(fmt.Sprintf("item%d", i+1)); err != nil {
+			t.Fatalf("tx.PushBack failed: %v", err)
+		}
+	}
+
+	// 提交前底层存储不应有任何写入
+	if meta, _ := list.getMeta(); meta.TotalCount != 0 {
+		t.Fatalf("expected no writes before commit, got TotalCount=%d", meta.TotalCount)
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	meta, _ := list.getMeta()
+	if meta.TotalCount != 4 || meta.LastPageNumber != 2 {
+		t.Errorf("meta mismatch after commit: %+v", meta)
+	}
+	if last, _ := list.GetLast(); last != "item4" {
+		t.Errorf("expected last item 'item4', got %q", last)
+	}
+
+	// 事务已关闭，不能再次提交或继续操作
+	if err := tx.Commit(); !errors.Is(err, ErrTxClosed) {
+		t.Errorf("expected ErrTxClosed on double commit, got %v", err)
+	}
+	if err := tx.PushBack("item5"); !errors.Is(err, ErrTxClosed) {
+		t.Errorf("expected ErrTxClosed on PushBack after commit, got %v", err)
+	}
+}
+
+func TestTxRollback(t *testing.T) {
 	store := NewMockStateStore()
-	return NewList("perf_test", pageSize, store), store
+	list := NewList[string]("test_list", 3, store)
+	_ = list.PushBack("item1")
+
+	tx, err := list.Begin()
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	_ = tx.PushBack("item2")
+	_ = tx.Set(0, "item1-modified")
+	tx.Rollback()
+
+	// 回滚后底层存储应保持事务开始前的状态
+	length, _ := list.Length()
+	if length != 1 {
+		t.Errorf("expected length 1 after rollback, got %d", length)
+	}
+	first, _ := list.Get(0)
+	if first != "item1" {
+		t.Errorf("expected unmodified 'item1' after rollback, got %q", first)
+	}
 }
 
-// ------------------------------ 插入性能测试 ------------------------------
+func TestTxPushBackBatchAndSet(t *testing.T) {
+	store := NewMockStateStore()
+	list := NewList[string]("test_list", 3, store)
 
-func BenchmarkInsert_SmallPage(b *testing.B) {
-	benchmarkInsert(b, SmallPageSize)
+	err := list.Update(func(tx *Tx[string]) error {
+		if err := tx.PushBackBatch([]string{"item1", "item2", "item3", "item4"}); err != nil {
+			return err
+		}
+		return tx.Set(3, "item4-updated")
+	})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	value, err := list.Get(3)
+	if err != nil || value != "item4-updated" {
+		t.Errorf("Get(3) => (%q, %v), expected ('item4-updated', nil)", value, err)
+	}
+
+	// Set 越界应报错且不影响已有数据
+	err = list.Update(func(tx *Tx[string]) error {
+		return tx.Set(10, "x")
+	})
+	if !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("expected ErrIndexOutOfRange, got %v", err)
+	}
 }
 
-func BenchmarkInsert_LargePage(b *testing.B) {
-	benchmarkInsert(b, LargePageSize)
+// TestTxSetRejectedAfterHeadSkewOrTombstone 验证 Set 在列表出现头部跳过
+// 或墓碑之后会直接报错，而不是用 index/pageSize 的算术定位悄悄写坏数据
+func TestTxSetRejectedAfterHeadSkewOrTombstone(t *testing.T) {
+	t.Run("PopFront之后", func(t *testing.T) {
+		store := NewMockStateStore()
+		list := NewList[string]("tx_set_skew_list", 3, store)
+		for i := 1; i <= 6; i++ {
+			_ = list.PushBack(fmt.Sprintf("item%d", i))
+		}
+		if _, err := list.PopFront(); err != nil {
+			t.Fatalf("PopFront failed: %v", err)
+		}
+
+		err := list.Update(func(tx *Tx[string]) error {
+			return tx.Set(0, "REPLACED")
+		})
+		if !errors.Is(err, ErrTxUnsupportedAfterSkew) {
+			t.Errorf("Set() error = %v, want ErrTxUnsupportedAfterSkew", err)
+		}
+
+		// 被拒绝的 Set 不应该写坏任何数据
+		value, getErr := list.Get(0)
+		if getErr != nil || value != "item2" {
+			t.Errorf("Get(0) = (%q, %v), want (item2, nil)", value, getErr)
+		}
+	})
+
+	t.Run("Sparse墓碑之后", func(t *testing.T) {
+		store := NewMockStateStore()
+		list := NewList[string]("tx_set_tombstone_list", 3, store, WithIndexMode[string](Sparse))
+		for i := 1; i <= 6; i++ {
+			_ = list.PushBack(fmt.Sprintf("item%d", i))
+		}
+		if err := list.RemoveAt(2); err != nil {
+			t.Fatalf("RemoveAt failed: %v", err)
+		}
+
+		err := list.Update(func(tx *Tx[string]) error {
+			return tx.Set(0, "REPLACED")
+		})
+		if !errors.Is(err, ErrTxUnsupportedAfterSkew) {
+			t.Errorf("Set() error = %v, want ErrTxUnsupportedAfterSkew", err)
+		}
+	})
 }
 
-func benchmarkInsert(b *testing.B, pageSize int) {
-	list, _ := initList(pageSize)
-	b.ResetTimer()
+// TestTxPushBackRejectedAfterHeadSkewOrTombstone 验证 Tx.PushBack 在列表
+// 发生过 PopFront 头部跳过或 Sparse 墓碑之后会拒绝写入而不是悄悄写坏数据：
+// tx.loadPage 读到的是经过头部跳过/墓碑过滤之后的逻辑视图，一旦按这个
+// 视图的长度误判末页是否写满，就会把新元素写进错误的物理偏移，覆盖或
+// 丢失其他槽位的数据（旧实现正是这样，在 pageSize=2、6 次 PushBack 后
+// 再 3 次 PopFront 的场景下，新元素与既有数据发生物理偏移错位）
+func TestTxPushBackRejectedAfterHeadSkewOrTombstone(t *testing.T) {
+	t.Run("PopFront跨页之后", func(t *testing.T) {
+		store := NewMockStateStore()
+		list := NewList[string]("tx_pushback_skew_list", 2, store)
+		for i := 1; i <= 6; i++ {
+			if err := list.PushBack(fmt.Sprintf("item%d", i)); err != nil {
+				t.Fatalf("PushBack failed: %v", err)
+			}
+		}
+		for i := 0; i < 3; i++ {
+			if _, err := list.PopFront(); err != nil {
+				t.Fatalf("PopFront failed: %v", err)
+			}
+		}
 
-	for i := 0; i < b.N; i++ {
-		// 批量插入测试(每次测试迭代插入TotalItems个元素)
-		start := time.Now()
-		for n := 0; n < TotalItems; n++ {
-			_ = list.PushBack(strconv.Itoa(n))
+		err := list.Update(func(tx *Tx[string]) error {
+			return tx.PushBack("NEWVIATX")
+		})
+		if !errors.Is(err, ErrTxUnsupportedAfterSkew) {
+			t.Errorf("PushBack() error = %v, want ErrTxUnsupportedAfterSkew", err)
+		}
+
+		// 被拒绝的 PushBack 不应该写坏任何现存数据，也不应该让列表增长
+		length, err := list.Length()
+		if err != nil || length != 3 {
+			t.Fatalf("Length() = %d, %v, want 3, nil", length, err)
+		}
+		want := []string{"item4", "item5", "item6"}
+		for i, w := range want {
+			value, err := list.Get(i)
+			if err != nil || value != w {
+				t.Errorf("Get(%d) = (%q, %v), want (%q, nil)", i, value, err, w)
+			}
+		}
+	})
+
+	t.Run("Sparse墓碑之后", func(t *testing.T) {
+		store := NewMockStateStore()
+		list := NewList[string]("tx_pushback_tombstone_list", 2, store, WithIndexMode[string](Sparse))
+		for i := 1; i <= 6; i++ {
+			if err := list.PushBack(fmt.Sprintf("item%d", i)); err != nil {
+				t.Fatalf("PushBack failed: %v", err)
+			}
+		}
+		if err := list.RemoveAt(2); err != nil {
+			t.Fatalf("RemoveAt failed: %v", err)
+		}
+
+		err := list.Update(func(tx *Tx[string]) error {
+			return tx.PushBack("NEWVIATX")
+		})
+		if !errors.Is(err, ErrTxUnsupportedAfterSkew) {
+			t.Errorf("PushBack() error = %v, want ErrTxUnsupportedAfterSkew", err)
+		}
+
+		// 被拒绝的 PushBack 既不应该写坏数据也不应该复用墓碑槽位
+		length, err := list.Length()
+		if err != nil || length != 5 {
+			t.Fatalf("Length() = %d, %v, want 5, nil", length, err)
+		}
+		want := []string{"item1", "item2", "item4", "item5", "item6"}
+		for i, w := range want {
+			value, err := list.Get(i)
+			if err != nil || value != w {
+				t.Errorf("Get(%d) = (%q, %v), want (%q, nil)", i, value, err, w)
+			}
+		}
+	})
+}
+
+// TestIndexedListPushBackRejectedAfterRemoveAtFirst 验证 IndexedList.PushBack
+// 在 RemoveAt(0) 让主列表产生头部跳过之后会整体失败，而不是像修复前那样
+// 报告成功却让索引指向一个读不出来的位置（Get 返回 ErrPageNotFound）
+func TestIndexedListPushBackRejectedAfterRemoveAtFirst(t *testing.T) {
+	store := NewMockStateStore()
+	list := NewIndexedList[testUser]("idx_pushback_skew_users", 3, store, userSpecs())
+
+	users := []testUser{
+		{Name: "alice", City: "sh"},
+		{Name: "bob", City: "bj"},
+		{Name: "carol", City: "sh"},
+		{Name: "dave", City: "gz"},
+	}
+	for _, u := range users {
+		if err := list.PushBack(u); err != nil {
+			t.Fatalf("PushBack(%+v) failed: %v", u, err)
+		}
+	}
+
+	if err := list.RemoveAt(0); err != nil {
+		t.Fatalf("RemoveAt(0) failed: %v", err)
+	}
+
+	err := list.PushBack(testUser{Name: "erin", City: "sh"})
+	if !errors.Is(err, ErrTxUnsupportedAfterSkew) {
+		t.Errorf("PushBack() error = %v, want ErrTxUnsupportedAfterSkew", err)
+	}
+
+	// 失败的 PushBack 不应该让索引指向任何读不出来的位置
+	ids, err := list.QueryByIndex("city", "sh")
+	if err != nil {
+		t.Fatalf("QueryByIndex failed: %v", err)
+	}
+	for _, id := range ids {
+		if _, err := list.Get(id); err != nil {
+			t.Errorf("Get(%d) failed after rejected PushBack: %v", id, err)
 		}
-		b.ReportMetric(float64(time.Since(start).Milliseconds())/float64(TotalItems), "ms/op")
 	}
 }
 
-// ------------------------------ 查询性能测试 ------------------------------
+func TestTxUpdateRollsBackOnError(t *testing.T) {
+	store := NewMockStateStore()
+	list := NewList[string]("test_list", 3, store)
 
-func BenchmarkQuery_SmallPage(b *testing.B) {
-	benchmarkQuery(b, SmallPageSize)
+	wantErr := errors.New("boom")
+	err := list.Update(func(tx *Tx[string]) error {
+		_ = tx.PushBack("item1")
+		return wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Errorf("expected Update to propagate fn error, got %v", err)
+	}
+
+	length, _ := list.Length()
+	if length != 0 {
+		t.Errorf("expected no writes after failed Update, got length %d", length)
+	}
 }
 
-func BenchmarkQuery_LargePage(b *testing.B) {
-	benchmarkQuery(b, LargePageSize)
+func TestTxUsesBatchStateStore(t *testing.T) {
+	batchStore := NewMockBatchStateStore()
+	list := NewList[string]("test_list", 3, batchStore)
+
+	err := list.Update(func(tx *Tx[string]) error {
+		return tx.PushBackBatch([]string{"item1", "item2", "item3", "item4"})
+	})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	// 4 次追加跨 2 个页 + 1 次元数据更新，去重后应只触发一次 PutStateBatch 调用
+	if batchStore.putBatchCalls != 1 {
+		t.Errorf("expected exactly 1 PutStateBatch call, got %d", batchStore.putBatchCalls)
+	}
+	if batchStore.putCalls != 0 {
+		t.Errorf("expected PutState to be bypassed when PutStateBatch is available, got %d calls", batchStore.putCalls)
+	}
 }
 
-func benchmarkQuery(b *testing.B, pageSize int) {
-	list, _ := initList(pageSize)
-	prepareTestData(list, TotalItems)
-	b.ResetTimer()
+// asset 用于验证 List[T] 在结构化元素场景下的编解码行为
+type asset struct {
+	ID    string
+	Value int
+}
 
-	for i := 0; i < b.N; i++ {
-		// 随机查询不同位置的元素
-		start := time.Now()
-		for n := 0; n < SamplePoints; n++ {
-			index := n * (TotalItems / SamplePoints)
-			_, _ = list.Get(index)
+func TestListStructElementWithJSONCodec(t *testing.T) {
+	store := NewMockStateStore()
+	list := NewList[asset]("asset_list", 3, store)
+
+	want := asset{ID: "a1", Value: 100}
+	if err := list.PushBack(want); err != nil {
+		t.Fatalf("PushBack failed: %v", err)
+	}
+
+	got, err := list.Get(0)
+	if err != nil || got != want {
+		t.Errorf("Get(0) => (%+v, %v), expected (%+v, nil)", got, err, want)
+	}
+}
+
+func TestListGobCodec(t *testing.T) {
+	store := NewMockStateStore()
+	list := NewList[asset]("asset_list_gob", 3, store,
+		WithCodec[asset](GobCodec[asset]{}),
+		WithPageFramer[asset](LengthPrefixedFramer{}),
+	)
+
+	items := []asset{{ID: "a1", Value: 1}, {ID: "a2", Value: 2}, {ID: "a3", Value: 3}, {ID: "a4", Value: 4}}
+	for _, item := range items {
+		if err := list.PushBack(item); err != nil {
+			t.Fatalf("PushBack failed: %v", err)
+		}
+	}
+
+	for i, want := range items {
+		got, err := list.Get(i)
+		if err != nil || got != want {
+			t.Errorf("Get(%d) => (%+v, %v), expected (%+v, nil)", i, got, err, want)
 		}
-		b.ReportMetric(float64(time.Since(start).Milliseconds())/float64(SamplePoints), "ms/op")
 	}
 }
 
-// ------------------------------ 遍历性能测试 ------------------------------
+func TestListRawBytesCodec(t *testing.T) {
+	store := NewMockStateStore()
+	list := NewList[[]byte]("raw_list", 3, store,
+		WithCodec[[]byte](RawBytesCodec{}),
+		WithPageFramer[[]byte](LengthPrefixedFramer{}),
+	)
 
-func BenchmarkIterate_SmallPage(b *testing.B) {
-	benchmarkIterate(b, SmallPageSize)
+	want := []byte{0x00, 0x01, 0xFF, 0x10}
+	if err := list.PushBack(want); err != nil {
+		t.Fatalf("PushBack failed: %v", err)
+	}
+
+	got, err := list.Get(0)
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Get(0) => (%v, %v), expected (%v, nil)", got, err, want)
+	}
 }
 
-func BenchmarkIterate_LargePage(b *testing.B) {
-	benchmarkIterate(b, LargePageSize)
+func TestJSONArrayFramerCompatibility(t *testing.T) {
+	// 验证 JSONArrayFramer 打包结果与早期版本 json.Marshal([]string) 的格式逐字节兼容
+	framer := JSONArrayFramer{}
+	codec := JSONCodec[string]{}
+
+	values := []string{"item1", "item2", "item3"}
+	elements := make([][]byte, len(values))
+	for i, v := range values {
+		encoded, err := codec.Encode(v)
+		if err != nil {
+			t.Fatalf("encode failed: %v", err)
+		}
+		elements[i] = encoded
+	}
+
+	got, err := framer.EncodePage(elements)
+	if err != nil {
+		t.Fatalf("EncodePage failed: %v", err)
+	}
+
+	want, _ := json.Marshal(values)
+	if string(got) != string(want) {
+		t.Errorf("expected page envelope %s, got %s", want, got)
+	}
 }
 
-func benchmarkIterate(b *testing.B, pageSize int) {
-	list, _ := initList(pageSize)
-	prepareTestData(list, TotalItems)
-	b.ResetTimer()
+func TestLengthPrefixedFramerRoundTrip(t *testing.T) {
+	framer := LengthPrefixedFramer{}
+	elements := [][]byte{{}, {0x01}, {0x02, 0x03, 0x04}}
 
-	for i := 0; i < b.N; i++ {
-		start := time.Now()
-		_ = list.Range(0, -1, func(_ int, _ string) error {
-			return nil
-		})
+	encoded, err := framer.EncodePage(elements)
+	if err != nil {
+		t.Fatalf("EncodePage failed: %v", err)
+	}
+
+	decoded, err := framer.DecodePage(encoded)
+	if err != nil {
+		t.Fatalf("DecodePage failed: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, elements) {
+		t.Errorf("expected %v, got %v", elements, decoded)
+	}
+}
+
+func TestCursorForwardAndBackward(t *testing.T) {
+	store := NewMockStateStore()
+	list := NewList[string]("cursor_list", 3, store)
+	for i := 0; i < 5; i++ {
+		_ = list.PushBack(fmt.Sprintf("item%d", i+1))
+	}
+
+	cursor, err := list.Cursor()
+	if err != nil {
+		t.Fatalf("Cursor failed: %v", err)
+	}
+
+	t.Run("正向遍历", func(t *testing.T) {
+		var got []string
+		for ok := cursor.First(); ok; ok = cursor.Next() {
+			value, err := cursor.Value()
+			if err != nil {
+				t.Fatalf("Value failed: %v", err)
+			}
+			got = append(got, value)
+		}
+		want := []string{"item1", "item2", "item3", "item4", "item5"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("逆向遍历", func(t *testing.T) {
+		var got []string
+		for ok := cursor.Last(); ok; ok = cursor.Prev() {
+			value, err := cursor.Value()
+			if err != nil {
+				t.Fatalf("Value failed: %v", err)
+			}
+			got = append(got, value)
+		}
+		want := []string{"item5", "item4", "item3", "item2", "item1"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("Seek越界使游标失效", func(t *testing.T) {
+		if cursor.Seek(5) {
+			t.Errorf("expected Seek(5) to fail for a 5-element list")
+		}
+		if cursor.Valid() {
+			t.Errorf("expected cursor to be invalid after out-of-range Seek")
+		}
+		if _, err := cursor.Value(); !errors.Is(err, ErrIndexOutOfRange) {
+			t.Errorf("expected ErrIndexOutOfRange, got %v", err)
+		}
+	})
+}
+
+func TestCursorTokenRoundTrip(t *testing.T) {
+	store := NewMockStateStore()
+	list := NewList[string]("cursor_token_list", 3, store)
+	for i := 0; i < 5; i++ {
+		_ = list.PushBack(fmt.Sprintf("item%d", i+1))
+	}
+
+	cursor, _ := list.Cursor()
+	cursor.Seek(3)
+
+	token, err := cursor.Token()
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+
+	resumed, err := list.CursorFrom(token)
+	if err != nil {
+		t.Fatalf("CursorFrom failed: %v", err)
+	}
+	if !resumed.Valid() {
+		t.Fatalf("expected resumed cursor to be valid")
+	}
+	if resumed.SnapshotGrew() {
+		t.Errorf("expected SnapshotGrew to be false when list is unchanged")
+	}
+
+	value, err := resumed.Value()
+	if err != nil || value != "item4" {
+		t.Errorf("Value() => (%q, %v), expected ('item4', nil)", value, err)
+	}
+}
+
+func TestCursorSnapshotGrowsAfterToken(t *testing.T) {
+	store := NewMockStateStore()
+	list := NewList[string]("cursor_grow_list", 3, store)
+	for i := 0; i < 3; i++ {
+		_ = list.PushBack(fmt.Sprintf("item%d", i+1))
+	}
+
+	cursor, _ := list.Cursor()
+	cursor.Last()
+	token, err := cursor.Token()
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+
+	// token 生成之后列表继续增长
+	_ = list.PushBack("item4")
+	_ = list.PushBack("item5")
+
+	resumed, err := list.CursorFrom(token)
+	if err != nil {
+		t.Fatalf("CursorFrom failed: %v", err)
+	}
+	if !resumed.SnapshotGrew() {
+		t.Errorf("expected SnapshotGrew to be true after list grew past the token snapshot")
+	}
+
+	// 恢复后的游标以最新总量为边界，可以继续向后遍历到新增元素
+	var got []string
+	for ok := true; ok; ok = resumed.Next() {
+		value, err := resumed.Value()
+		if err != nil {
+			t.Fatalf("Value failed: %v", err)
+		}
+		got = append(got, value)
+	}
+	want := []string{"item3", "item4", "item5"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+// TestCursorAfterPopFrontAndTombstone 验证 Cursor/GetRange 在列表发生过
+// 头部跳过（PopFront）或 Sparse 墓碑（RemoveAt）之后仍能返回正确的、
+// 紧凑编号的逻辑序列，而不是 ErrPageNotFound 或错位的元素
+func TestCursorAfterPopFrontAndTombstone(t *testing.T) {
+	t.Run("PopFront跨页之后", func(t *testing.T) {
+		store := NewMockStateStore()
+		list := NewList[string]("cursor_popfront_list", 2, store)
+		for i := 1; i <= 6; i++ {
+			_ = list.PushBack(fmt.Sprintf("item%d", i))
+		}
+		for i := 0; i < 3; i++ {
+			if _, err := list.PopFront(); err != nil {
+				t.Fatalf("PopFront failed: %v", err)
+			}
+		}
+
+		cursor, err := list.Cursor()
+		if err != nil {
+			t.Fatalf("Cursor failed: %v", err)
+		}
+		var got []string
+		for ok := cursor.Valid(); ok; ok = cursor.Next() {
+			value, err := cursor.Value()
+			if err != nil {
+				t.Fatalf("Value failed: %v", err)
+			}
+			got = append(got, value)
+		}
+		want := []string{"item4", "item5", "item6"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+
+		items, nextToken, err := list.GetRange(nil, 10)
+		if err != nil {
+			t.Fatalf("GetRange failed: %v", err)
+		}
+		if nextToken != nil {
+			t.Errorf("expected nextToken to be nil, got %v", nextToken)
+		}
+		if !reflect.DeepEqual(items, want) {
+			t.Errorf("GetRange() = %v, want %v", items, want)
+		}
+	})
+
+	t.Run("Sparse墓碑之后", func(t *testing.T) {
+		store := NewMockStateStore()
+		list := NewList[string]("cursor_tombstone_list", 4, store, WithIndexMode[string](Sparse))
+		for i := 1; i <= 6; i++ {
+			_ = list.PushBack(fmt.Sprintf("item%d", i))
+		}
+		if err := list.RemoveAt(1); err != nil {
+			t.Fatalf("RemoveAt failed: %v", err)
+		}
+
+		cursor, err := list.Cursor()
+		if err != nil {
+			t.Fatalf("Cursor failed: %v", err)
+		}
+		var got []string
+		for ok := cursor.Valid(); ok; ok = cursor.Next() {
+			value, err := cursor.Value()
+			if err != nil {
+				t.Fatalf("Value failed: %v", err)
+			}
+			got = append(got, value)
+		}
+		want := []string{"item1", "item3", "item4", "item5", "item6"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	})
+}
+
+func TestGetRangePagination(t *testing.T) {
+	store := NewMockStateStore()
+	list := NewList[string]("get_range_list", 3, store)
+	for i := 0; i < 7; i++ {
+		_ = list.PushBack(fmt.Sprintf("item%d", i+1))
+	}
+
+	var all []string
+	var token []byte
+	for {
+		items, nextToken, err := list.GetRange(token, 3)
+		if err != nil {
+			t.Fatalf("GetRange failed: %v", err)
+		}
+		all = append(all, items...)
+		if nextToken == nil {
+			break
+		}
+		token = nextToken
+	}
+
+	want := []string{"item1", "item2", "item3", "item4", "item5", "item6", "item7"}
+	if !reflect.DeepEqual(all, want) {
+		t.Errorf("expected %v, got %v", want, all)
+	}
+}
+
+func TestPopBackAndPopFront(t *testing.T) {
+	store := NewMockStateStore()
+	list := NewList[string]("pop_list", 3, store)
+	for i := 1; i <= 7; i++ {
+		_ = list.PushBack(fmt.Sprintf("item%d", i))
+	}
+
+	back, err := list.PopBack()
+	if err != nil || back != "item7" {
+		t.Fatalf("PopBack() = %q, %v, want item7, nil", back, err)
+	}
+	front, err := list.PopFront()
+	if err != nil || front != "item1" {
+		t.Fatalf("PopFront() = %q, %v, want item1, nil", front, err)
+	}
+
+	length, err := list.Length()
+	if err != nil || length != 5 {
+		t.Fatalf("Length() = %d, %v, want 5, nil", length, err)
+	}
+
+	var remaining []string
+	_ = list.Range(0, -1, func(_ int, value string) error {
+		remaining = append(remaining, value)
+		return nil
+	})
+	want := []string{"item2", "item3", "item4", "item5", "item6"}
+	if !reflect.DeepEqual(remaining, want) {
+		t.Errorf("expected %v, got %v", want, remaining)
+	}
+}
+
+func TestPopFromEmptyList(t *testing.T) {
+	store := NewMockStateStore()
+	list := NewList[string]("empty_pop_list", 3, store)
+
+	if _, err := list.PopBack(); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("PopBack() on empty list error = %v, want ErrIndexOutOfRange", err)
+	}
+	if _, err := list.PopFront(); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("PopFront() on empty list error = %v, want ErrIndexOutOfRange", err)
+	}
+}
+
+// TestPushBackAfterListDrained 验证列表被 PopFront/PopBack 弹空之后，
+// 头部跳过位置会被清零，后续 PushBack 仍能从第 1 页正常写入，而不会
+// 因为残留的 FirstPageNumber 指向一个已不存在的页而丢失数据
+func TestPushBackAfterListDrained(t *testing.T) {
+	store := NewMockStateStore()
+	list := NewList[string]("drained_list", 2, store)
+	for i := 1; i <= 3; i++ {
+		_ = list.PushBack(fmt.Sprintf("item%d", i))
+	}
+
+	for {
+		if _, err := list.PopFront(); err != nil {
+			break
+		}
+	}
+
+	if err := list.PushBack("fresh1"); err != nil {
+		t.Fatalf("PushBack after drain failed: %v", err)
+	}
+	value, err := list.Get(0)
+	if err != nil || value != "fresh1" {
+		t.Fatalf("Get(0) = %q, %v, want fresh1, nil", value, err)
+	}
+}
+
+func TestRemoveAtDenseMode(t *testing.T) {
+	store := NewMockStateStore()
+	list := NewList[string]("remove_dense_list", 3, store)
+	for i := 1; i <= 7; i++ {
+		_ = list.PushBack(fmt.Sprintf("item%d", i))
+	}
+
+	if err := list.RemoveAt(3); err != nil {
+		t.Fatalf("RemoveAt(3) failed: %v", err)
+	}
+
+	length, err := list.Length()
+	if err != nil || length != 6 {
+		t.Fatalf("Length() = %d, %v, want 6, nil", length, err)
+	}
+
+	var remaining []string
+	_ = list.Range(0, -1, func(_ int, value string) error {
+		remaining = append(remaining, value)
+		return nil
+	})
+	want := []string{"item1", "item2", "item3", "item5", "item6", "item7"}
+	if !reflect.DeepEqual(remaining, want) {
+		t.Errorf("expected %v, got %v", want, remaining)
+	}
+
+	// Dense 模式下 RemoveAt 会重建物理布局，之后 GetPage 的页码划分也应重新紧凑
+	page1, err := list.GetPage(1)
+	if err != nil {
+		t.Fatalf("GetPage(1) failed: %v", err)
+	}
+	if !reflect.DeepEqual(page1, []string{"item1", "item2", "item3"}) {
+		t.Errorf("GetPage(1) = %v, want [item1 item2 item3]", page1)
+	}
+}
+
+func TestRemoveAtSparseModeReusesFreeSlot(t *testing.T) {
+	store := NewMockStateStore()
+	list := NewList[string]("remove_sparse_list", 3, store, WithIndexMode[string](Sparse))
+	for i := 1; i <= 7; i++ {
+		_ = list.PushBack(fmt.Sprintf("item%d", i))
+	}
+
+	if err := list.RemoveAt(3); err != nil {
+		t.Fatalf("RemoveAt(3) failed: %v", err)
+	}
+
+	var remaining []string
+	_ = list.Range(0, -1, func(_ int, value string) error {
+		remaining = append(remaining, value)
+		return nil
+	})
+	want := []string{"item1", "item2", "item3", "item5", "item6", "item7"}
+	if !reflect.DeepEqual(remaining, want) {
+		t.Errorf("expected %v, got %v", want, remaining)
+	}
+
+	// PushBack 应复用刚刚释放的墓碑槽位，而不是在末尾新增物理页
+	if err := list.PushBack("item8"); err != nil {
+		t.Fatalf("PushBack failed: %v", err)
+	}
+	meta, err := list.getMeta()
+	if err != nil {
+		t.Fatalf("getMeta failed: %v", err)
+	}
+	if len(meta.FreeSlots) != 0 {
+		t.Errorf("FreeSlots = %v, want empty after reuse", meta.FreeSlots)
+	}
+	if meta.LastPageNumber != 3 {
+		t.Errorf("LastPageNumber = %d, want 3 (reused slot should not open a new page)", meta.LastPageNumber)
+	}
+
+	var all []string
+	_ = list.Range(0, -1, func(_ int, value string) error {
+		all = append(all, value)
+		return nil
+	})
+	// 复用的是 page2 的第一个槽位（原先的 item4），因此 item8 物理上插入
+	// 在 item5 之前，而不是列表的逻辑末尾——这是 Sparse 模式优先压缩存储
+	// 而非严格保序换来的已知取舍
+	wantAll := []string{"item1", "item2", "item3", "item8", "item5", "item6", "item7"}
+	if !reflect.DeepEqual(all, wantAll) {
+		t.Errorf("expected %v, got %v", wantAll, all)
+	}
+}
+
+// TestGetSparseUsesPageCountSkipTable 验证 Sparse 模式下 Get 按逻辑索引
+// 定位目标页时，靠 meta.PageCounts 这张跳表在内存中完成，不会随列表长度
+// 或墓碑密度退化成逐页读取状态——无论墓碑落在哪一页，Get 都应该只产生
+// 与 Dense 模式同等数量级（与页数无关）的 GetState 调用
+func TestGetSparseUsesPageCountSkipTable(t *testing.T) {
+	store := newCountingStateStore()
+	list := NewList[int]("sparse_skiptable_list", 10, store, WithIndexMode[int](Sparse))
+	for i := 0; i < 1000; i++ {
+		if err := list.PushBack(i); err != nil {
+			t.Fatalf("PushBack failed: %v", err)
+		}
+	}
+	// 在列表前部制造几个稀疏分布的墓碑，不影响目标索引 990 的逻辑位置
+	for _, idx := range []int{5, 123, 456, 789} {
+		if err := list.RemoveAt(idx); err != nil {
+			t.Fatalf("RemoveAt(%d) failed: %v", idx, err)
+		}
+	}
+
+	length, err := list.Length()
+	if err != nil || length != 996 {
+		t.Fatalf("Length() = %d, %v, want 996, nil", length, err)
+	}
+
+	store.getCalls = 0
+	value, err := list.Get(990)
+	if err != nil {
+		t.Fatalf("Get(990) failed: %v", err)
+	}
+	if value != 994 {
+		t.Errorf("Get(990) = %d, want 994 (4 个墓碑之前的元素整体前移)", value)
+	}
+
+	// getMeta 在 Get/GetPage 中各被调用一次，加上目标页本身的一次状态
+	// 读取，总数应是一个与列表长度/墓碑数量无关的小常数，而不是随二者
+	// 增长的逐页扫描次数
+	if store.getCalls > 5 {
+		t.Errorf("Get(990) issued %d GetState calls, want a small constant independent of list length/tombstone count", store.getCalls)
+	}
+}
+
+// TestInterleavedPushPopWorkload 在 Dense 与 Sparse 两种模式下交替执行
+// PushBack/PopFront/PopBack（RemoveAt 只在 Dense 模式下执行），并在每一步
+// 之后用 Range 校验列表内容的完整性，覆盖头部跳过与追加、弹出交织的场景；
+// Sparse 模式下不产生墓碑，墓碑复用不在本测试覆盖范围内
+func TestInterleavedPushPopWorkload(t *testing.T) {
+	for _, mode := range []IndexMode{Dense, Sparse} {
+		mode := mode
+		t.Run(map[IndexMode]string{Dense: "Dense", Sparse: "Sparse"}[mode], func(t *testing.T) {
+			store := NewMockStateStore()
+			list := NewList[int]("interleaved_list", 4, store, WithIndexMode[int](mode))
+
+			var model []int
+			push := func(v int) {
+				_ = list.PushBack(v)
+				model = append(model, v)
+			}
+			popFront := func() {
+				v, err := list.PopFront()
+				if err != nil {
+					t.Fatalf("PopFront failed: %v", err)
+				}
+				if v != model[0] {
+					t.Fatalf("PopFront() = %d, want %d", v, model[0])
+				}
+				model = model[1:]
+			}
+			popBack := func() {
+				v, err := list.PopBack()
+				if err != nil {
+					t.Fatalf("PopBack failed: %v", err)
+				}
+				if v != model[len(model)-1] {
+					t.Fatalf("PopBack() = %d, want %d", v, model[len(model)-1])
+				}
+				model = model[:len(model)-1]
+			}
+			removeMiddle := func() {
+				mid := len(model) / 2
+				if err := list.RemoveAt(mid); err != nil {
+					t.Fatalf("RemoveAt(%d) failed: %v", mid, err)
+				}
+				model = append(append([]int{}, model[:mid]...), model[mid+1:]...)
+			}
+			verify := func() {
+				var got []int
+				_ = list.Range(0, -1, func(_ int, value int) error {
+					got = append(got, value)
+					return nil
+				})
+				if !reflect.DeepEqual(got, model) {
+					t.Fatalf("Range() = %v, want %v", got, model)
+				}
+				length, err := list.Length()
+				if err != nil || length != len(model) {
+					t.Fatalf("Length() = %d, %v, want %d, nil", length, err, len(model))
+				}
+			}
+
+			for i := 0; i < 20; i++ {
+				push(i)
+			}
+			verify()
+			popFront()
+			popFront()
+			popBack()
+			verify()
+			// Sparse 模式下 RemoveAt 之后的 PushBack 会优先复用被腾出的物理
+			// 槽位（见 TestRemoveAtSparseModeReusesFreeSlot），新元素不一定
+			// 落在逻辑末尾，因此这里只在 Dense 模式下继续做严格保序校验
+			if mode == Dense && len(model) >= 3 {
+				removeMiddle()
+				verify()
+			}
+			for i := 100; i < 106; i++ {
+				push(i)
+			}
+			verify()
+			for len(model) > 2 {
+				popFront()
+			}
+			verify()
+		})
+	}
+}
+
+// BenchmarkPopFront 衡量 Dense 与 Sparse 两种模式下 PopFront 头部跳过的
+// 开销（RemoveAt(0) 在两种模式下都走同一条路径），用于验证其均摊成本不随
+// 列表增长而显著上升
+func BenchmarkPopFront(b *testing.B) {
+	for _, mode := range []IndexMode{Dense, Sparse} {
+		b.Run(map[IndexMode]string{Dense: "Dense", Sparse: "Sparse"}[mode], func(b *testing.B) {
+			store := NewMockStateStore()
+			list := NewList[string]("bench_popfront_list", SmallPageSize, store, WithIndexMode[string](mode))
+			prepareTestData(list, b.N+1)
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				if _, err := list.PopFront(); err != nil {
+					b.Fatalf("PopFront failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+// ------------------------------ IndexedList 测试 ------------------------------
+
+// testUser 是 IndexedList 测试使用的样例实体，City 用于单值索引，
+// Tags 用于多值索引
+type testUser struct {
+	Name string
+	City string
+	Tags []string
+}
+
+func userSpecs() []IndexSpec[testUser] {
+	return []IndexSpec[testUser]{
+		{
+			Name:    "city",
+			Extract: func(u testUser) []string { return []string{u.City} },
+		},
+		{
+			Name:    "name",
+			Extract: func(u testUser) []string { return []string{u.Name} },
+			Unique:  true,
+		},
+		{
+			Name:    "tag",
+			Extract: func(u testUser) []string { return u.Tags },
+		},
+	}
+}
+
+func TestIndexedListPushBackAndQueryByIndex(t *testing.T) {
+	store := NewMockStateStore()
+	list := NewIndexedList[testUser]("users", 3, store, userSpecs())
+
+	users := []testUser{
+		{Name: "alice", City: "sh", Tags: []string{"vip", "new"}},
+		{Name: "bob", City: "bj", Tags: []string{"vip"}},
+		{Name: "carol", City: "sh", Tags: []string{"new"}},
+	}
+	for _, u := range users {
+		if err := list.PushBack(u); err != nil {
+			t.Fatalf("PushBack(%+v) failed: %v", u, err)
+		}
+	}
+
+	sh, err := list.QueryByIndex("city", "sh")
+	if err != nil {
+		t.Fatalf("QueryByIndex(city, sh) failed: %v", err)
+	}
+	if !reflect.DeepEqual(sh, []int{0, 2}) {
+		t.Errorf("QueryByIndex(city, sh) = %v, want [0 2]", sh)
+	}
+
+	vip, err := list.QueryByIndex("tag", "vip")
+	if err != nil {
+		t.Fatalf("QueryByIndex(tag, vip) failed: %v", err)
+	}
+	if !reflect.DeepEqual(vip, []int{0, 1}) {
+		t.Errorf("QueryByIndex(tag, vip) = %v, want [0 1]", vip)
+	}
+
+	name, err := list.QueryByIndex("name", "bob")
+	if err != nil {
+		t.Fatalf("QueryByIndex(name, bob) failed: %v", err)
+	}
+	if !reflect.DeepEqual(name, []int{1}) {
+		t.Errorf("QueryByIndex(name, bob) = %v, want [1]", name)
+	}
+
+	if _, err := list.QueryByIndex("missing", "x"); err == nil {
+		t.Errorf("QueryByIndex with unknown index name should fail")
+	}
+}
+
+func TestIndexedListUniqueIndexConflict(t *testing.T) {
+	store := NewMockStateStore()
+	list := NewIndexedList[testUser]("users", 3, store, userSpecs())
+
+	if err := list.PushBack(testUser{Name: "alice", City: "sh"}); err != nil {
+		t.Fatalf("PushBack failed: %v", err)
+	}
+	err := list.PushBack(testUser{Name: "alice", City: "bj"})
+	if !errors.Is(err, ErrUniqueIndexViolation) {
+		t.Fatalf("PushBack with duplicate unique key = %v, want ErrUniqueIndexViolation", err)
+	}
+
+	// 冲突的写入不应该留下任何痕迹：长度不变，city 索引也不应记录 bj
+	length, _ := list.Length()
+	if length != 1 {
+		t.Errorf("Length() = %d, want 1 after rejected PushBack", length)
+	}
+	bj, err := list.QueryByIndex("city", "bj")
+	if err != nil || len(bj) != 0 {
+		t.Errorf("QueryByIndex(city, bj) = %v, %v, want [], nil", bj, err)
+	}
+}
+
+func TestIndexedListRemoveAtRenumbersOtherIndexes(t *testing.T) {
+	store := NewMockStateStore()
+	list := NewIndexedList[testUser]("users", 3, store, userSpecs())
+
+	users := []testUser{
+		{Name: "alice", City: "sh"},
+		{Name: "bob", City: "sh"},
+		{Name: "carol", City: "sh"},
+		{Name: "dave", City: "bj"},
+	}
+	for _, u := range users {
+		if err := list.PushBack(u); err != nil {
+			t.Fatalf("PushBack(%+v) failed: %v", u, err)
+		}
+	}
+
+	// 删除 bob（index 1），carol/dave 的逻辑位置各自前移一位
+	if err := list.RemoveAt(1); err != nil {
+		t.Fatalf("RemoveAt(1) failed: %v", err)
+	}
+
+	sh, err := list.QueryByIndex("city", "sh")
+	if err != nil {
+		t.Fatalf("QueryByIndex(city, sh) failed: %v", err)
+	}
+	if !reflect.DeepEqual(sh, []int{0, 1}) {
+		t.Errorf("QueryByIndex(city, sh) = %v, want [0 1] (alice, carol 前移后)", sh)
+	}
+
+	bj, err := list.QueryByIndex("city", "bj")
+	if err != nil {
+		t.Fatalf("QueryByIndex(city, bj) failed: %v", err)
+	}
+	if !reflect.DeepEqual(bj, []int{2}) {
+		t.Errorf("QueryByIndex(city, bj) = %v, want [2] (dave 前移后)", bj)
+	}
+
+	// bob 的 name 索引应已被清理
+	bobResult, err := list.QueryByIndex("name", "bob")
+	if err != nil || len(bobResult) != 0 {
+		t.Errorf("QueryByIndex(name, bob) = %v, %v, want [], nil", bobResult, err)
+	}
+}
+
+func TestIndexedListQueryANDOR(t *testing.T) {
+	store := NewMockStateStore()
+	list := NewIndexedList[testUser]("users", 3, store, userSpecs())
+
+	users := []testUser{
+		{Name: "alice", City: "sh", Tags: []string{"vip"}},
+		{Name: "bob", City: "bj", Tags: []string{"vip"}},
+		{Name: "carol", City: "sh", Tags: []string{"new"}},
+	}
+	for _, u := range users {
+		if err := list.PushBack(u); err != nil {
+			t.Fatalf("PushBack(%+v) failed: %v", u, err)
+		}
+	}
+
+	and, err := list.QueryAND(IndexQuery{Name: "city", Key: "sh"}, IndexQuery{Name: "tag", Key: "vip"})
+	if err != nil {
+		t.Fatalf("QueryAND failed: %v", err)
+	}
+	if !reflect.DeepEqual(and, []int{0}) {
+		t.Errorf("QueryAND(city=sh, tag=vip) = %v, want [0]", and)
+	}
+
+	or, err := list.QueryOR(IndexQuery{Name: "city", Key: "bj"}, IndexQuery{Name: "tag", Key: "new"})
+	if err != nil {
+		t.Fatalf("QueryOR failed: %v", err)
+	}
+	if !reflect.DeepEqual(or, []int{1, 2}) {
+		t.Errorf("QueryOR(city=bj, tag=new) = %v, want [1 2]", or)
+	}
+}
+
+func TestIndexedListQueryByIndexPaged(t *testing.T) {
+	store := NewMockStateStore()
+	list := NewIndexedList[testUser]("users", 3, store, userSpecs())
+
+	for i := 0; i < 5; i++ {
+		u := testUser{Name: "user" + strconv.Itoa(i), City: "sh"}
+		if err := list.PushBack(u); err != nil {
+			t.Fatalf("PushBack failed: %v", err)
+		}
+	}
+
+	var got []int
+	var token []byte
+	for {
+		page, nextToken, err := list.QueryByIndexPaged("city", "sh", token, 2)
+		if err != nil {
+			t.Fatalf("QueryByIndexPaged failed: %v", err)
+		}
+		got = append(got, page...)
+		if nextToken == nil {
+			break
+		}
+		token = nextToken
+	}
+	if !reflect.DeepEqual(got, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("paged QueryByIndex accumulated = %v, want [0 1 2 3 4]", got)
+	}
+}
+
+// failingBatchStateStore 包装 mockBatchStateStore，可配置在第 failOnCall
+// 次 PutStateBatch 调用时失败且不写入任何内容，用于模拟批量提交过程中
+// 发生崩溃的场景，验证 IndexedList 跨主列表与多个倒排列表的写入确实被
+// 合并为一次原子的 PutStateBatch 调用
+type failingBatchStateStore struct {
+	*mockBatchStateStore
+	failOnCall int
+	calls      int
+}
+
+func (m *failingBatchStateStore) PutStateBatch(kvs map[string][]byte) error {
+	m.calls++
+	if m.failOnCall > 0 && m.calls == m.failOnCall {
+		return errors.New("simulated crash during batch commit")
+	}
+	return m.mockBatchStateStore.PutStateBatch(kvs)
+}
+
+func TestIndexedListPushBackAtomicOnBatchFailure(t *testing.T) {
+	store := &failingBatchStateStore{mockBatchStateStore: NewMockBatchStateStore(), failOnCall: 1}
+	list := NewIndexedList[testUser]("users", 4, store, userSpecs())
+
+	err := list.PushBack(testUser{Name: "alice", City: "sh", Tags: []string{"vip"}})
+	if err == nil {
+		t.Fatalf("expected PushBack to fail due to simulated batch crash")
+	}
+
+	length, err := list.Length()
+	if err != nil || length != 0 {
+		t.Fatalf("Length() = %d, %v, want 0, nil after failed PushBack", length, err)
+	}
+	if results, err := list.QueryByIndex("city", "sh"); err != nil || len(results) != 0 {
+		t.Fatalf("QueryByIndex(city, sh) = %v, %v, want [], nil after failed PushBack", results, err)
+	}
+
+	// 故障恢复后正常写入应不受影响，且只触发了预期次数的 PutStateBatch 调用
+	store.failOnCall = 0
+	if err := list.PushBack(testUser{Name: "alice", City: "sh", Tags: []string{"vip"}}); err != nil {
+		t.Fatalf("PushBack failed after fault injection cleared: %v", err)
+	}
+	results, err := list.QueryByIndex("city", "sh")
+	if err != nil || !reflect.DeepEqual(results, []int{0}) {
+		t.Errorf("QueryByIndex(city, sh) = %v, %v, want [0], nil", results, err)
+	}
+	if store.calls != 2 {
+		t.Errorf("expected 2 PutStateBatch calls (1 failed + 1 succeeded), got %d", store.calls)
+	}
+}
+
+// 压测参数配置
+const (
+	TotalItems    = 100000 // 总测试数据量
+	SmallPageSize = 10     // 小分页配置
+	LargePageSize = 1000   // 大分页配置
+	SamplePoints  = 100    // 采样点数量
+)
+
+// 初始化测试列表
+func initList(pageSize int) (*List[string], StateStore) {
+	store := NewMockStateStore()
+	return NewList[string]("perf_test", pageSize, store), store
+}
+
+// ------------------------------ 插入性能测试 ------------------------------
+
+func BenchmarkInsert_SmallPage(b *testing.B) {
+	benchmarkInsert(b, SmallPageSize)
+}
+
+func BenchmarkInsert_LargePage(b *testing.B) {
+	benchmarkInsert(b, LargePageSize)
+}
+
+func benchmarkInsert(b *testing.B, pageSize int) {
+	list, _ := initList(pageSize)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		// 批量插入测试(每次测试迭代插入TotalItems个元素)
+		start := time.Now()
+		for n := 0; n < TotalItems; n++ {
+			_ = list.PushBack(strconv.Itoa(n))
+		}
+		b.ReportMetric(float64(time.Since(start).Milliseconds())/float64(TotalItems), "ms/op")
+	}
+}
+
+// BenchmarkInsert_Tx_SmallPage 与 BenchmarkInsert_SmallPage 对照，
+// 衡量将 TotalItems 次 PushBack 合并进单个事务后摊薄掉的每元素状态写入开销
+func BenchmarkInsert_Tx_SmallPage(b *testing.B) {
+	benchmarkInsertTx(b, SmallPageSize)
+}
+
+func BenchmarkInsert_Tx_LargePage(b *testing.B) {
+	benchmarkInsertTx(b, LargePageSize)
+}
+
+func benchmarkInsertTx(b *testing.B, pageSize int) {
+	list, _ := initList(pageSize)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		start := time.Now()
+		_ = list.Update(func(tx *Tx[string]) error {
+			for n := 0; n < TotalItems; n++ {
+				if err := tx.PushBack(strconv.Itoa(n)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		b.ReportMetric(float64(time.Since(start).Milliseconds())/float64(TotalItems), "ms/op")
+	}
+}
+
+// ------------------------------ 编解码器性能对比测试 ------------------------------
+
+// benchAsset 是编解码器对比测试使用的结构化元素
+type benchAsset struct {
+	ID    string
+	Value int
+}
+
+func BenchmarkCodec_JSON_SmallPage(b *testing.B) {
+	benchmarkCodecInsert(b, SmallPageSize, WithCodec[benchAsset](JSONCodec[benchAsset]{}))
+}
+
+func BenchmarkCodec_JSON_LargePage(b *testing.B) {
+	benchmarkCodecInsert(b, LargePageSize, WithCodec[benchAsset](JSONCodec[benchAsset]{}))
+}
+
+func BenchmarkCodec_Gob_SmallPage(b *testing.B) {
+	benchmarkCodecInsert(b, SmallPageSize,
+		WithCodec[benchAsset](GobCodec[benchAsset]{}),
+		WithPageFramer[benchAsset](LengthPrefixedFramer{}))
+}
+
+func BenchmarkCodec_Gob_LargePage(b *testing.B) {
+	benchmarkCodecInsert(b, LargePageSize,
+		WithCodec[benchAsset](GobCodec[benchAsset]{}),
+		WithPageFramer[benchAsset](LengthPrefixedFramer{}))
+}
+
+func benchmarkCodecInsert(b *testing.B, pageSize int, opts ...Option[benchAsset]) {
+	const itemCount = 10000
+
+	store := NewMockStateStore()
+	list := NewList[benchAsset]("codec_bench", pageSize, store, opts...)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		start := time.Now()
+		for n := 0; n < itemCount; n++ {
+			_ = list.PushBack(benchAsset{ID: strconv.Itoa(n), Value: n})
+		}
+		b.ReportMetric(float64(time.Since(start).Milliseconds())/float64(itemCount), "ms/op")
+	}
+}
+
+// BenchmarkCodec_RawBytes 对比跳过编解码往返的 RawBytesCodec，元素是预先
+// 序列化好的固定大小负载，衡量相较 JSON/Gob 省去编解码步骤带来的收益
+func BenchmarkCodec_RawBytes_SmallPage(b *testing.B) {
+	benchmarkRawBytesInsert(b, SmallPageSize)
+}
+
+func BenchmarkCodec_RawBytes_LargePage(b *testing.B) {
+	benchmarkRawBytesInsert(b, LargePageSize)
+}
+
+func benchmarkRawBytesInsert(b *testing.B, pageSize int) {
+	const itemCount = 10000
+
+	store := NewMockStateStore()
+	list := NewList[[]byte]("codec_bench_raw", pageSize, store,
+		WithCodec[[]byte](RawBytesCodec{}),
+		WithPageFramer[[]byte](LengthPrefixedFramer{}))
+	payload := []byte(`{"ID":"fixed","Value":0}`)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		start := time.Now()
+		for n := 0; n < itemCount; n++ {
+			_ = list.PushBack(payload)
+		}
+		b.ReportMetric(float64(time.Since(start).Milliseconds())/float64(itemCount), "ms/op")
+	}
+}
+
+// ------------------------------ 查询性能测试 ------------------------------
+
+func BenchmarkQuery_SmallPage(b *testing.B) {
+	benchmarkQuery(b, SmallPageSize)
+}
+
+func BenchmarkQuery_LargePage(b *testing.B) {
+	benchmarkQuery(b, LargePageSize)
+}
+
+func benchmarkQuery(b *testing.B, pageSize int) {
+	list, _ := initList(pageSize)
+	prepareTestData(list, TotalItems)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		// 随机查询不同位置的元素
+		start := time.Now()
+		for n := 0; n < SamplePoints; n++ {
+			index := n * (TotalItems / SamplePoints)
+			_, _ = list.Get(index)
+		}
+		b.ReportMetric(float64(time.Since(start).Milliseconds())/float64(SamplePoints), "ms/op")
+	}
+}
+
+// ------------------------------ 遍历性能测试 ------------------------------
+
+func BenchmarkIterate_SmallPage(b *testing.B) {
+	benchmarkIterate(b, SmallPageSize)
+}
+
+func BenchmarkIterate_LargePage(b *testing.B) {
+	benchmarkIterate(b, LargePageSize)
+}
+
+func benchmarkIterate(b *testing.B, pageSize int) {
+	list, _ := initList(pageSize)
+	prepareTestData(list, TotalItems)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		start := time.Now()
+		_ = list.Range(0, -1, func(_ int, _ string) error {
+			return nil
+		})
+		b.ReportMetric(float64(time.Since(start).Milliseconds()), "ms/op")
+	}
+}
+
+// BenchmarkIterate_Cursor_SmallPage 与 BenchmarkIterate_SmallPage 对照，
+// 衡量 Cursor 逐元素遍历的开销：Cursor 缓存当前页，同页内相邻移动不重复
+// 调用 GetPage（与 Range 一样每页一次），但每次 Value 都会重新读取元数据
+func BenchmarkIterate_Cursor_SmallPage(b *testing.B) {
+	benchmarkIterateCursor(b, SmallPageSize)
+}
+
+func BenchmarkIterate_Cursor_LargePage(b *testing.B) {
+	benchmarkIterateCursor(b, LargePageSize)
+}
+
+func benchmarkIterateCursor(b *testing.B, pageSize int) {
+	list, _ := initList(pageSize)
+	prepareTestData(list, TotalItems)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		start := time.Now()
+		cursor, _ := list.Cursor()
+		for ok := cursor.Valid(); ok; ok = cursor.Next() {
+			_, _ = cursor.Value()
+		}
 		b.ReportMetric(float64(time.Since(start).Milliseconds()), "ms/op")
 	}
 }
@@ -412,7 +1759,7 @@ func benchmarkIterate(b *testing.B, pageSize int) {
 // ------------------------------ 工具函数 ------------------------------
 
 // 准备测试数据
-func prepareTestData(list *List, count int) {
+func prepareTestData(list *List[string], count int) {
 	for i := 0; i < count; i++ {
 		_ = list.PushBack("data-" + strconv.Itoa(i))
 	}
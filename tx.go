@@ -0,0 +1,273 @@
+package smartpagelist
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrTxClosed 表示事务已提交或回滚，不能再继续操作
+var ErrTxClosed = errors.New("transaction closed")
+
+// ErrTxUnsupportedAfterSkew 表示列表已经发生过头部跳过（PopFront/
+// RemoveAt(0)）或在 Sparse 模式下产生过墓碑（RemoveAt）。Tx 内的 Set 依赖
+// index/pageSize 的算术定位，PushBack 依赖 tx.loadPage 读到的末页长度，
+// 二者都假设物理布局与逻辑视图一致，一旦出现头部跳过或墓碑这个假设就不
+// 再成立，继续写入会悄悄覆盖或丢失其他槽位的数据，因此直接拒绝而不是
+// 返回一个看似成功实则破坏数据的结果
+var ErrTxUnsupportedAfterSkew = errors.New("Set/PushBack are not supported in a Tx once the list has head skew or tombstones; use List.PushBack/PopFront/RemoveAt outside a Tx instead")
+
+// BatchStateStore 是 StateStore 的可选扩展接口，用于批量提交多个键值对
+// 底层存储若实现该接口，Tx.Commit 会优先调用 PutStateBatch 以减少状态写入次数
+type BatchStateStore interface {
+	PutStateBatch(kvs map[string][]byte) error
+}
+
+// Tx 表示一次针对 List[T] 的事务性批量操作
+//
+// PushBack / PushBackBatch / Set 等方法只在内存中缓冲页数据与元数据的变更，
+// 对同一页的多次修改会被去重合并为一次写入，直到 Commit 时才统一落盘，
+// 从而将 PushBack 原本每次 2~3 次 PutState 的开销摊薄为每个被修改的页与
+// 元数据各一次 PutState（或在 BatchStateStore 可用时仅一次批量调用）。
+type Tx[T any] struct {
+	list *List[T]
+
+	meta      *listMeta
+	metaDirty bool
+
+	// pages 缓存每页已解码的元素，Set/PushBack 在其上直接修改
+	pages      map[int][]T
+	dirtyPages map[int]bool
+
+	closed bool
+}
+
+// Begin 创建一个绑定到当前列表的新事务
+func (l *List[T]) Begin() (*Tx[T], error) {
+	meta, err := l.getMeta()
+	if err != nil {
+		return nil, err
+	}
+	return &Tx[T]{
+		list:       l,
+		meta:       meta,
+		pages:      make(map[int][]T),
+		dirtyPages: make(map[int]bool),
+	}, nil
+}
+
+// Update 在一个事务内执行 fn，fn 返回非 nil 错误时自动回滚，否则自动提交
+func (l *List[T]) Update(fn func(tx *Tx[T]) error) error {
+	tx, err := l.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// loadPage 返回事务缓冲区中的页数据，缺失时从底层列表加载并缓存
+func (tx *Tx[T]) loadPage(pageNumber int) ([]T, error) {
+	if values, ok := tx.pages[pageNumber]; ok {
+		return values, nil
+	}
+
+	values, err := tx.list.GetPage(pageNumber)
+	if err != nil {
+		if !errors.Is(err, ErrPageNotFound) {
+			return nil, err
+		}
+		values = nil
+	}
+
+	tx.pages[pageNumber] = values
+	return values, nil
+}
+
+// GetPage 读取指定页，优先返回事务内尚未提交的最新内容
+func (tx *Tx[T]) GetPage(pageNumber int) ([]T, error) {
+	if tx.closed {
+		return nil, ErrTxClosed
+	}
+	if pageNumber < 1 {
+		return nil, errors.New("page number must be >= 1")
+	}
+	if pageNumber > tx.meta.LastPageNumber {
+		return nil, ErrPageNotFound
+	}
+	return tx.loadPage(pageNumber)
+}
+
+// PushBack 在事务内追加元素，变更只保存在内存缓冲区中，等待 Commit 落盘
+//
+// 注意：tx.loadPage 读取的是经过 List.GetPage 过滤头部跳过与 Sparse 墓碑
+// 之后的逻辑视图，而不是原始物理槽位数组；一旦列表出现过头部跳过
+// （FirstPageNumber/HeadOffset）或 Sparse 墓碑（FreeSlots），末页的物理
+// 长度就可能与这个过滤后的长度不一致（首页与末页重合时尤为明显），继续
+// 按过滤后的视图追加会把新元素写进错误的物理偏移，覆盖真正的数据。
+// 因此与 Tx.Set 一致，直接返回 ErrTxUnsupportedAfterSkew 而不是悄悄写坏
+// 数据；此时请改用 List.PushBack（非事务）代替
+func (tx *Tx[T]) PushBack(value T) error {
+	if tx.closed {
+		return ErrTxClosed
+	}
+	if tx.meta.FirstPageNumber != 0 || tx.meta.HeadOffset != 0 || len(tx.meta.FreeSlots) > 0 {
+		return ErrTxUnsupportedAfterSkew
+	}
+
+	targetPage := tx.meta.LastPageNumber
+	if tx.meta.TotalCount%tx.list.pageSize == 0 {
+		targetPage++
+	}
+
+	values, err := tx.loadPage(targetPage)
+	if err != nil {
+		return err
+	}
+
+	values = append(values, value)
+	tx.pages[targetPage] = values
+	tx.dirtyPages[targetPage] = true
+
+	tx.meta.LastPageNumber = targetPage
+	tx.meta.TotalCount++
+	tx.list.setPageCount(tx.meta, targetPage, len(values))
+	tx.metaDirty = true
+	return nil
+}
+
+// PushBackBatch 在事务内依次追加多个元素，等价于多次调用 PushBack
+// 但避免了每个元素都重新判断事务状态之外的额外开销
+func (tx *Tx[T]) PushBackBatch(values []T) error {
+	for _, value := range values {
+		if err := tx.PushBack(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Set 在事务内按索引覆盖写入元素
+//
+// 注意：定位逻辑基于 index/pageSize 的算术换算，未感知 PopFront 产生的头部
+// 跳过（FirstPageNumber/HeadOffset）与 Sparse 模式产生的墓碑（FreeSlots），
+// 一旦列表出现这类偏移就不能再用算术定位，此时返回 ErrTxUnsupportedAfterSkew
+// 而不是悄悄写坏数据；随机访问场景此时请改用 Get 或 RemoveAt+PushBack
+func (tx *Tx[T]) Set(index int, value T) error {
+	if tx.closed {
+		return ErrTxClosed
+	}
+	if index < 0 || index >= tx.meta.TotalCount {
+		return ErrIndexOutOfRange
+	}
+	if tx.meta.FirstPageNumber != 0 || tx.meta.HeadOffset != 0 || len(tx.meta.FreeSlots) > 0 {
+		return ErrTxUnsupportedAfterSkew
+	}
+
+	pageNumber, offset := tx.list.locate(index)
+	values, err := tx.loadPage(pageNumber)
+	if err != nil {
+		return err
+	}
+	if offset >= len(values) {
+		return fmt.Errorf("data inconsistency detected [key:%s, page:%d, offset:%d]: page has %d items", tx.list.key, pageNumber, offset, len(values))
+	}
+
+	values[offset] = value
+	tx.pages[pageNumber] = values
+	tx.dirtyPages[pageNumber] = true
+	return nil
+}
+
+// Commit 将事务缓冲区中的所有变更一次性写入底层存储
+// 如果 StateStore 实现了 BatchStateStore，则通过一次 PutStateBatch 调用完成；
+// 否则退化为逐个 key 调用 PutState
+func (tx *Tx[T]) Commit() error {
+	if tx.closed {
+		return ErrTxClosed
+	}
+	tx.closed = true
+
+	writes, err := tx.stagedWrites()
+	if err != nil {
+		return err
+	}
+	return commitWrites(tx.list.store, tx.list.key, writes)
+}
+
+// stagedWrites 计算事务缓冲区对应的待提交键值对，但不执行任何写入
+// 也不将事务标记为已关闭，供需要把多个 Tx（如 IndexedList 跨主列表与若干
+// 二级索引列表）的变更合并为同一次 commitWrites 调用的上层场景复用
+func (tx *Tx[T]) stagedWrites() (map[string][]byte, error) {
+	if len(tx.dirtyPages) == 0 && !tx.metaDirty {
+		return nil, nil
+	}
+
+	writes := make(map[string][]byte, len(tx.dirtyPages)+1)
+	for pageNumber := range tx.dirtyPages {
+		values := tx.pages[pageNumber]
+		elements := make([][]byte, len(values))
+		for i, value := range values {
+			encoded, err := tx.list.codec.Encode(value)
+			if err != nil {
+				return nil, fmt.Errorf("encode value failed [page:%d, offset:%d]: %w", pageNumber, i, err)
+			}
+			elements[i] = encoded
+		}
+
+		pageData, err := tx.list.framer.EncodePage(elements)
+		if err != nil {
+			return nil, fmt.Errorf("encode page envelope failed [page:%d]: %w", pageNumber, err)
+		}
+		writes[tx.list.buildPageKey(pageNumber)] = pageData
+	}
+
+	if tx.metaDirty {
+		metaData, err := json.Marshal(tx.meta)
+		if err != nil {
+			return nil, fmt.Errorf("marshal meta failed: %w", err)
+		}
+		writes[tx.list.metaKey()] = metaData
+	}
+
+	return writes, nil
+}
+
+// commitWrites 将 writes 中的键值对提交到 store
+// 如果 store 实现了 BatchStateStore，则通过一次 PutStateBatch 调用完成，
+// 多键写入的原子性由该调用保证；否则退化为逐个 key 调用 PutState，此时
+// 不再保证多键写入的原子性
+func commitWrites(store StateStore, key string, writes map[string][]byte) error {
+	if len(writes) == 0 {
+		return nil
+	}
+
+	if batchStore, ok := store.(BatchStateStore); ok {
+		if err := batchStore.PutStateBatch(writes); err != nil {
+			return fmt.Errorf("put state batch failed [key:%s]: %w", key, err)
+		}
+		return nil
+	}
+
+	for k, v := range writes {
+		if err := store.PutState(k, v); err != nil {
+			return fmt.Errorf("save state failed [key:%s]: %w", k, err)
+		}
+	}
+	return nil
+}
+
+// Rollback 丢弃事务缓冲区中的所有未提交变更
+func (tx *Tx[T]) Rollback() {
+	if tx.closed {
+		return
+	}
+	tx.closed = true
+	tx.pages = nil
+	tx.dirtyPages = nil
+	tx.meta = nil
+}